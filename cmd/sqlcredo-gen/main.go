@@ -0,0 +1,97 @@
+// Command sqlcredo-gen generates a typed repository for an entity struct:
+// column constants, a struct embedding sqlcredo.SQLCredo, its constructor
+// and a method for each named query of an optional .sql file.
+//
+// Typical use is a go:generate directive next to the entity:
+//
+//	//go:generate go run github.com/Klojer/sqlcredo/cmd/sqlcredo-gen -type User
+//
+//	//sqlcredo:repo table=users id=id queries=user_queries.sql
+//	type User struct { ... }
+//
+// Flags override the settings of the sqlcredo:repo directive.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Klojer/sqlcredo/internal/codegen"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "sqlcredo-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("sqlcredo-gen", flag.ContinueOnError)
+	typeName := flags.String("type", "", "entity struct name (required)")
+	tableName := flags.String("table", "", "table name")
+	idColumn := flags.String("id", "", "id column, \"id\" by default")
+	queriesFile := flags.String("queries", "", ".sql file of named queries")
+	repoName := flags.String("repo", "", "repository type name, <type>Repo by default")
+	out := flags.String("out", "", "output file, <type>_repo.gen.go by default")
+	dir := flags.String("dir", ".", "package directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *typeName == "" {
+		flags.Usage()
+		return fmt.Errorf("-type is required")
+	}
+
+	entity, err := codegen.ParseEntity(*dir, *typeName)
+	if err != nil {
+		return err
+	}
+
+	cfg := codegen.Config{
+		Entity:   entity,
+		Table:    setting(*tableName, entity.Annotation["table"], ""),
+		IDColumn: setting(*idColumn, entity.Annotation["id"], "id"),
+		Repo:     setting(*repoName, entity.Annotation["repo"], ""),
+	}
+	if cfg.Table == "" {
+		return fmt.Errorf("table of %s is not set, use -table or the sqlcredo:repo directive", *typeName)
+	}
+
+	if path := setting(*queriesFile, entity.Annotation["queries"], ""); path != "" {
+		f, err := os.Open(filepath.Join(*dir, path))
+		if err != nil {
+			return fmt.Errorf("unable to open queries: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+
+		cfg.Queries, err = codegen.ParseQueries(f)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", path, err)
+		}
+	}
+
+	src, err := codegen.Generate(cfg)
+	if err != nil {
+		return err
+	}
+
+	outPath := setting(*out, entity.Annotation["out"], strings.ToLower(*typeName)+"_repo.gen.go")
+	if err := os.WriteFile(filepath.Join(*dir, outPath), src, 0o644); err != nil {
+		return fmt.Errorf("unable to write %s: %w", outPath, err)
+	}
+	return nil
+}
+
+// setting returns the first non-empty value.
+func setting(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,188 @@
+package sqlcredo
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Klojer/sqlcredo/internal/structmap"
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// Column is a column mapped by a field of T. Columns are obtained from a ColumnSet,
+// so they always reference a column of T and renaming the field breaks the build
+// instead of the query.
+type Column[T any] struct {
+	name string
+}
+
+// Name returns the name of the column.
+func (c Column[T]) Name() string {
+	return c.name
+}
+
+func (c Column[T]) String() string {
+	return c.name
+}
+
+// Eq matches records where the column is equal to value.
+func (c Column[T]) Eq(value any) api.Filter { return api.Eq(c.name, value) }
+
+// Ne matches records where the column is not equal to value.
+func (c Column[T]) Ne(value any) api.Filter { return api.Ne(c.name, value) }
+
+// Gt matches records where the column is greater than value.
+func (c Column[T]) Gt(value any) api.Filter { return api.Gt(c.name, value) }
+
+// Gte matches records where the column is greater than or equal to value.
+func (c Column[T]) Gte(value any) api.Filter { return api.Gte(c.name, value) }
+
+// Lt matches records where the column is less than value.
+func (c Column[T]) Lt(value any) api.Filter { return api.Lt(c.name, value) }
+
+// Lte matches records where the column is less than or equal to value.
+func (c Column[T]) Lte(value any) api.Filter { return api.Lte(c.name, value) }
+
+// In matches records where the column is equal to any of the values.
+func (c Column[T]) In(values ...any) api.Filter { return api.In(c.name, values...) }
+
+// NotIn matches records where the column is equal to none of the values.
+func (c Column[T]) NotIn(values ...any) api.Filter { return api.NotIn(c.name, values...) }
+
+// Like matches records where the column matches the SQL LIKE pattern.
+func (c Column[T]) Like(pattern string) api.Filter { return api.Like(c.name, pattern) }
+
+// IsNull matches records where the column is NULL.
+func (c Column[T]) IsNull() api.Filter { return api.IsNull(c.name) }
+
+// IsNotNull matches records where the column is not NULL.
+func (c Column[T]) IsNotNull() api.Filter { return api.IsNotNull(c.name) }
+
+// Asc sorts pages by the column in ascending order.
+func (c Column[T]) Asc() api.PageOpt { return api.WithSortBy(c.name) }
+
+// Desc sorts pages by the column in descending order.
+// The order applies to all sort columns of the page.
+func (c Column[T]) Desc() api.PageOpt {
+	return func(p *api.PageParams) {
+		api.WithSortBy(c.name)(p)
+		api.WithSortDesc(c.name)(p)
+	}
+}
+
+// Select limits loaded columns to the given ones, see api.Select.
+func Select[T any](columns ...Column[T]) api.QueryOpt {
+	return api.Select(names(columns)...)
+}
+
+// WithColumns limits the columns loaded by paging to the given ones, see api.WithColumns.
+func WithColumns[T any](columns ...Column[T]) api.PageOpt {
+	return api.WithColumns(names(columns)...)
+}
+
+func names[T any](columns []Column[T]) []string {
+	res := make([]string, 0, len(columns))
+	for _, c := range columns {
+		res = append(res, c.name)
+	}
+	return res
+}
+
+// ColumnSet resolves the columns of T from its fields.
+type ColumnSet[T any] struct {
+	model   *T
+	byField map[fieldKey]Column[T]
+	all     []Column[T]
+}
+
+// fieldKey identifies a field of the model; fields at the same address,
+// like an embedded struct and its first field, differ in type.
+type fieldKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// Columns returns the column set of T, which must be a struct.
+//
+// Example:
+//
+//	cols := sqlcredo.Columns[User]()
+//	u := cols.Model()
+//	firstName := cols.Field(&u.FirstName)
+//
+//	page, err := repo.GetPage(ctx, firstName.Desc(), api.WithFilter(firstName.Like("Jo%")))
+func Columns[T any]() *ColumnSet[T] {
+	s := &ColumnSet[T]{
+		model:   new(T),
+		byField: map[fieldKey]Column[T]{},
+	}
+
+	model := reflect.ValueOf(s.model).Elem()
+	if model.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("sqlcredo: columns of %s: not a struct", model.Type()))
+	}
+
+	for _, f := range structmap.Fields(model.Type()) {
+		field := structmap.FieldByIndex(model, f.Index)
+		c := Column[T]{name: f.Column}
+		s.byField[fieldKey{addr: field.Addr().Pointer(), typ: field.Addr().Type()}] = c
+		s.all = append(s.all, c)
+	}
+	return s
+}
+
+// Model returns the instance of T whose field pointers are passed to Field.
+func (s *ColumnSet[T]) Model() *T {
+	return s.model
+}
+
+// Field returns the column mapped by the field ptr points to, which must be
+// a field of Model. It panics otherwise, so wrong references fail when the
+// columns are declared, typically at package initialization.
+func (s *ColumnSet[T]) Field(ptr any) Column[T] {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer {
+		panic(fmt.Sprintf("sqlcredo: column of %T: not a pointer to a field", ptr))
+	}
+	c, ok := s.byField[fieldKey{addr: v.Pointer(), typ: v.Type()}]
+	if !ok {
+		panic(fmt.Sprintf("sqlcredo: column of %T: not a column-mapped field of the model of %T", ptr, s.model))
+	}
+	return c
+}
+
+// ByName returns the column with the given name, failing with api.ErrUnknownColumn
+// if no field of T maps it. Useful to validate column names from requests.
+func (s *ColumnSet[T]) ByName(name string) (Column[T], error) {
+	for _, c := range s.all {
+		if c.name == name {
+			return c, nil
+		}
+	}
+	var zero T
+	return Column[T]{}, fmt.Errorf("%w: %q in %T", api.ErrUnknownColumn, name, zero)
+}
+
+// All returns the columns of T in field order.
+func (s *ColumnSet[T]) All() []Column[T] {
+	return append([]Column[T](nil), s.all...)
+}
+
+// Patch holds new values of some columns of T, applied with SQLCredo.Patch.
+type Patch[T any] struct {
+	values map[string]any
+}
+
+// Set returns a patch that also sets the column to value.
+func (p Patch[T]) Set(column Column[T], value any) Patch[T] {
+	values := make(map[string]any, len(p.values)+1)
+	for k, v := range p.values {
+		values[k] = v
+	}
+	values[column.name] = value
+	return Patch[T]{values: values}
+}
+
+// Values returns the new values by column name.
+func (p Patch[T]) Values() map[string]any {
+	return p.values
+}
@@ -0,0 +1,82 @@
+package sqlcredo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Named struct {
+	Name string `db:"name"`
+}
+
+type columnEntity struct {
+	ID string `db:"id"`
+	*Named
+	Nickname *string `db:"nickname"`
+	Ignored  string  `db:"-"`
+}
+
+func TestColumns(t *testing.T) {
+	cols := sqlcredo.Columns[columnEntity]()
+	e := cols.Model()
+
+	assert.Equal(t, "id", cols.Field(&e.ID).Name())
+	assert.Equal(t, "name", cols.Field(&e.Name).Name())
+	assert.Equal(t, "nickname", cols.Field(&e.Nickname).String())
+	assert.Len(t, cols.All(), 3)
+
+	assert.Panics(t, func() { cols.Field(&e.Ignored) })
+	assert.Panics(t, func() { cols.Field(e.Named) })
+	assert.Panics(t, func() { cols.Field(e.ID) })
+
+	c, err := cols.ByName("nickname")
+	require.NoError(t, err)
+	assert.Equal(t, cols.Field(&e.Nickname), c)
+	_, err = cols.ByName("email")
+	assert.ErrorIs(t, err, api.ErrUnknownColumn)
+}
+
+func TestColumns_NotStruct(t *testing.T) {
+	assert.Panics(t, func() { sqlcredo.Columns[string]() })
+}
+
+func TestColumn_Filters(t *testing.T) {
+	cols := sqlcredo.Columns[TestEntity]()
+	name := cols.Field(&cols.Model().Name)
+
+	assert.Equal(t, api.Eq("name", "John"), name.Eq("John"))
+	assert.Equal(t, api.In("name", "a", "b"), name.In("a", "b"))
+	assert.Equal(t, api.IsNull("name"), name.IsNull())
+}
+
+func TestSQLCredo_TypedColumns(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlcredo.NewSQLCredo[TestEntity, string](openSQLiteFile(t, "columns.db"), "sqlite3", "test_table", "id")
+	for _, e := range []TestEntity{{ID: "1", Name: "Ann"}, {ID: "2", Name: "Bob"}, {ID: "3", Name: "Carl"}} {
+		_, err := repo.Create(ctx, &e)
+		require.NoError(t, err)
+	}
+
+	cols := sqlcredo.Columns[TestEntity]()
+	id, name := cols.Field(&cols.Model().ID), cols.Field(&cols.Model().Name)
+
+	page, err := repo.GetPage(ctx, name.Desc(), api.WithFilter(name.Ne("Bob")))
+	require.NoError(t, err)
+	assert.Equal(t, []TestEntity{{ID: "3", Name: "Carl"}, {ID: "1", Name: "Ann"}}, page.Content)
+
+	ids, err := repo.GetAll(ctx, sqlcredo.Select(id), api.Where(name.Like("A%")))
+	require.NoError(t, err)
+	assert.Equal(t, []TestEntity{{ID: "1"}}, ids)
+
+	_, err = repo.Patch(ctx, "2", sqlcredo.Patch[TestEntity]{}.Set(name, "Bobby"))
+	require.NoError(t, err)
+	e, err := repo.GetByID(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, "Bobby", e.Name)
+}
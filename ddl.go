@@ -0,0 +1,19 @@
+package sqlcredo
+
+import (
+	"reflect"
+
+	"github.com/Klojer/sqlcredo/internal/ddl"
+)
+
+// GenerateSchema returns the CREATE TABLE DDL of T for the dialect ("sqlite3", "postgres" or "pgx"),
+// suitable for InitSchema. Column types follow the Go field types: pointers and sql.Null* types
+// are nullable, other fields are NOT NULL. The sqlcredo struct tag overrides the defaults:
+//
+//	Email string `db:"email" sqlcredo:"type:VARCHAR(255),unique,index"`
+//	Role  string `db:"role" sqlcredo:"default:'member'"`
+//
+// Columns tagged with index get a CREATE INDEX statement after the table.
+func GenerateSchema[T any](dialect string, table string, idColumn string) (string, error) {
+	return ddl.Generate(reflect.TypeOf((*T)(nil)).Elem(), dialect, table, idColumn)
+}
@@ -0,0 +1,102 @@
+package sqlcredo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type generatedEntity struct {
+	ID        string    `db:"id"`
+	Email     string    `db:"email" sqlcredo:"unique,index"`
+	Nickname  *string   `db:"nickname"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func TestGenerateSchema(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	schema, err := sqlcredo.GenerateSchema[generatedEntity]("sqlite3", "generated", "id")
+	require.NoError(t, err)
+
+	repo := sqlcredo.NewSQLCredo[generatedEntity, string](db, "sqlite3", "generated", "id")
+	_, err = repo.InitSchema(ctx, schema)
+	require.NoError(t, err)
+
+	created := generatedEntity{ID: "1", Email: "john@example.com", CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	_, err = repo.Create(ctx, &created)
+	require.NoError(t, err)
+	loaded, err := repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, created, loaded)
+
+	// unique emails
+	_, err = repo.Create(ctx, &generatedEntity{ID: "2", Email: "john@example.com", CreatedAt: time.Now()})
+	assert.Error(t, err)
+}
+
+func TestSQLCredo_Verify(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	repo := sqlcredo.NewSQLCredo[generatedEntity, string](db, "sqlite3", "generated", "id")
+	var drift *api.SchemaDriftError
+	require.ErrorAs(t, repo.Verify(ctx), &drift)
+	assert.Equal(t, []api.SchemaIssue{{Message: "table does not exist"}}, drift.Issues)
+
+	schema, err := sqlcredo.GenerateSchema[generatedEntity]("sqlite3", "generated", "id")
+	require.NoError(t, err)
+	_, err = repo.InitSchema(ctx, schema)
+	require.NoError(t, err)
+	assert.NoError(t, repo.Verify(ctx))
+}
+
+func TestSQLCredo_Verify_AutoincrementID(t *testing.T) {
+	type autoEntity struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	repo := sqlcredo.NewSQLCredo[autoEntity, int64](db, "sqlite3", "auto", "id")
+	_, err := repo.InitSchema(ctx,
+		"CREATE TABLE auto (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	assert.NoError(t, repo.Verify(ctx))
+}
+
+func TestSQLCredo_Verify_Drift(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	// email was renamed, nickname is NOT NULL, created_at is nullable, and there's no primary key
+	_, err := db.Exec(`CREATE TABLE generated (
+    id TEXT NOT NULL,
+    email_address TEXT NOT NULL,
+    nickname TEXT NOT NULL,
+    created_at TIMESTAMP NULL,
+    version INTEGER NOT NULL DEFAULT 1
+)`)
+	require.NoError(t, err)
+
+	err = sqlcredo.NewSQLCredo[generatedEntity, string](db, "sqlite3", "generated", "id").Verify(ctx)
+
+	assert.ErrorIs(t, err, api.ErrSchemaDrift)
+	var drift *api.SchemaDriftError
+	require.ErrorAs(t, err, &drift)
+	columns := make([]string, 0, len(drift.Issues))
+	for _, issue := range drift.Issues {
+		columns = append(columns, issue.Column)
+	}
+	assert.Equal(t, []string{"email", "nickname", "created_at", "id", "email_address"}, columns)
+}
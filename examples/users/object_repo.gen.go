@@ -0,0 +1,83 @@
+// Code generated by sqlcredo-gen. DO NOT EDIT.
+
+package users
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Klojer/sqlcredo"
+)
+
+// Columns of Object in the users table.
+const (
+	ObjectIDColumn        = "id"
+	ObjectFirstNameColumn = "first_name"
+	ObjectLastNameColumn  = "last_name"
+	ObjectBirthDateColumn = "birth_date"
+)
+
+// ObjectRepo is the repository of Object records in the users table.
+type ObjectRepo struct {
+	sqlcredo.SQLCredo[Object, Identity]
+}
+
+// NewObjectRepo creates the repository on db opened with driver.
+func NewObjectRepo(db *sql.DB, driver string) *ObjectRepo {
+	return &ObjectRepo{
+		SQLCredo: sqlcredo.NewSQLCredo[Object, Identity](db, driver, "users", ObjectIDColumn),
+	}
+}
+
+const objectRepoFindByLastNameQuery = `SELECT * FROM users WHERE last_name = :last_name ORDER BY id`
+
+// FindByLastName returns the users with the given last name ordered by id.
+func (r *ObjectRepo) FindByLastName(ctx context.Context, lastName string) ([]Object, error) {
+	var res []Object
+	if err := r.NamedSelectMany(ctx, &res, objectRepoFindByLastNameQuery, map[string]any{
+		"last_name": lastName,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to select records: %w", err)
+	}
+	return res, nil
+}
+
+const objectRepoCountBornAfterQuery = `SELECT COUNT(*) FROM users WHERE birth_date > :born_after`
+
+// CountBornAfter counts the users born after the given time.
+func (r *ObjectRepo) CountBornAfter(ctx context.Context, bornAfter time.Time) (int, error) {
+	var res int
+	if err := r.NamedSelectOne(ctx, &res, objectRepoCountBornAfterQuery, map[string]any{
+		"born_after": bornAfter,
+	}); err != nil {
+		return res, fmt.Errorf("unable to select record: %w", err)
+	}
+	return res, nil
+}
+
+const objectRepoRenameFirstNameQuery = `UPDATE users SET first_name = :new_name WHERE first_name = :old_name`
+
+// RenameFirstName runs the RenameFirstName query.
+func (r *ObjectRepo) RenameFirstName(ctx context.Context, oldName string, newName string) (sql.Result, error) {
+	res, err := r.NamedExec(ctx, objectRepoRenameFirstNameQuery, map[string]any{
+		"old_name": oldName,
+		"new_name": newName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	return res, nil
+}
+
+const objectRepoListAllQuery = `SELECT * FROM users ORDER BY id`
+
+// ListAll runs the ListAll query.
+func (r *ObjectRepo) ListAll(ctx context.Context) ([]Object, error) {
+	var res []Object
+	if err := r.SelectMany(ctx, &res, objectRepoListAllQuery); err != nil {
+		return nil, fmt.Errorf("unable to select records: %w", err)
+	}
+	return res, nil
+}
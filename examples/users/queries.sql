@@ -0,0 +1,18 @@
+-- name: FindByLastName :many
+-- FindByLastName returns the users with the given last name ordered by id.
+-- param: last_name string
+SELECT * FROM users WHERE last_name = :last_name ORDER BY id
+
+-- name: CountBornAfter :one
+-- CountBornAfter counts the users born after the given time.
+-- param: born_after time.Time
+-- result: int
+SELECT COUNT(*) FROM users WHERE birth_date > :born_after
+
+-- name: RenameFirstName :exec
+-- param: old_name string
+-- param: new_name string
+UPDATE users SET first_name = :new_name WHERE first_name = :old_name
+
+-- name: ListAll :many
+SELECT * FROM users ORDER BY id
@@ -17,6 +17,11 @@ const (
 
 type Identity string
 
+//go:generate go run ../../cmd/sqlcredo-gen -type Object
+
+// Object is a user record.
+//
+//sqlcredo:repo table=users id=id queries=queries.sql
 type Object struct {
 	ID        Identity  `db:"id"`
 	FirstName string    `db:"first_name"`
@@ -63,3 +68,33 @@ func (r *Repo) CountByLastNameExists(ctx context.Context) (map[string]int, error
 
 	return res, nil
 }
+
+const WithLastNameQuery = `SELECT * FROM users WHERE last_name IS NOT NULL`
+
+func (r *Repo) GetPageWithLastName(ctx context.Context, opts ...api.PageOpt) (api.Page[Object], error) {
+	page, err := sc.PageQuery[Object](ctx, r, WithLastNameQuery, nil, opts...)
+	if err != nil {
+		return page, fmt.Errorf("unable to select page: %w", err)
+	}
+	return page, nil
+}
+
+const FindByFirstNamesBornAfterQuery = `
+SELECT * FROM users
+WHERE first_name IN (:names) AND birth_date > :born_after
+ORDER BY id
+`
+
+func (r *Repo) FindByFirstNamesBornAfter(ctx context.Context,
+	names []string, bornAfter time.Time,
+) ([]Object, error) {
+	var res []Object
+	err := r.NamedSelectMany(ctx, &res, FindByFirstNamesBornAfterQuery, map[string]any{
+		"names":      names,
+		"born_after": bornAfter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to select records: %w", err)
+	}
+	return res, nil
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Klojer/sqlcredo/pkg/sqlcredotest"
+
 	_ "github.com/jackc/pgx/v5/stdlib"
 
 	"github.com/stretchr/testify/require"
@@ -48,12 +50,26 @@ func TestPostgres(t *testing.T) {
 		{name: "get-page-custom-order", run: CaseGetPageCustomOrder},
 		{name: "count-users", run: CaseCountUsers},
 		{name: "count-by-last-name-exists", run: CaseCountByLastNameExists},
+		{name: "exists-user", run: CaseExistsUser},
+		{name: "count-where", run: CaseCountWhere},
+		{name: "get-all-as-projection", run: CaseGetAllAsProjection},
+		{name: "get-page-as-projection", run: CaseGetPageAsProjection},
+		{name: "get-page-with-last-name", run: CaseGetPageWithLastName},
+		{name: "get-page-keyset", run: CaseGetPageKeyset},
+		{name: "find-by-first-names-born-after", run: CaseFindByFirstNamesBornAfter},
+		{name: "savepoints", run: CaseSavepoints},
+		{name: "verify-schema", run: CaseVerifySchema},
+		{name: "generated-repo", run: CaseGeneratedRepo},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.name, func(t *testing.T) {
 			tC.run(t, params)
 		})
 	}
+
+	t.Run("conformance", func(t *testing.T) {
+		sqlcredotest.RunConformance(t, db, pgDriver)
+	})
 }
 
 func createDB(t *testing.T) *sql.DB {
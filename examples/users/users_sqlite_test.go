@@ -45,6 +45,16 @@ func TestSqlite(t *testing.T) {
 		{name: "get-page-custom-order", run: CaseGetPageCustomOrder},
 		{name: "count-users", run: CaseCountUsers},
 		{name: "count-by-last-name-exists", run: CaseCountByLastNameExists},
+		{name: "exists-user", run: CaseExistsUser},
+		{name: "count-where", run: CaseCountWhere},
+		{name: "get-all-as-projection", run: CaseGetAllAsProjection},
+		{name: "get-page-as-projection", run: CaseGetPageAsProjection},
+		{name: "get-page-with-last-name", run: CaseGetPageWithLastName},
+		{name: "get-page-keyset", run: CaseGetPageKeyset},
+		{name: "find-by-first-names-born-after", run: CaseFindByFirstNamesBornAfter},
+		{name: "savepoints", run: CaseSavepoints},
+		{name: "verify-schema", run: CaseVerifySchema},
+		{name: "generated-repo", run: CaseGeneratedRepo},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.name, func(t *testing.T) {
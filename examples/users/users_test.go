@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	sc "github.com/Klojer/sqlcredo"
 	"github.com/Klojer/sqlcredo/examples/users"
 	"github.com/Klojer/sqlcredo/pkg/api"
 )
@@ -226,6 +227,78 @@ func CaseCountUsers(t *testing.T, params TestCaseParams) {
 	assert.Equal(t, len(c.TestUserPtrs), int(got))
 }
 
+func CaseExistsUser(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+
+	got, err := c.UnderTest.ExistsByID(ctx, c.TestUsers[2].ID)
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = c.UnderTest.ExistsByID(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.False(t, got)
+
+	got, err = c.UnderTest.Exists(ctx, api.And(
+		api.Eq("first_name", "Ann"), api.Eq("last_name", "Brick")))
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = c.UnderTest.Exists(ctx, api.And(
+		api.Eq("first_name", "Carl"), api.IsNotNull("last_name")))
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
+
+func CaseCountWhere(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+
+	got, err := c.UnderTest.CountWhere(ctx, api.Or(
+		api.Eq("first_name", "Ann"), api.IsNull("last_name")))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, int(got))
+
+	got, err = c.UnderTest.CountWhere(ctx, api.In("id"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, int(got))
+}
+
+type userName struct {
+	ID        users.Identity `db:"id"`
+	FirstName string         `db:"first_name"`
+}
+
+func CaseGetAllAsProjection(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+
+	got, err := sc.GetAllAs[userName](ctx, c.UnderTest)
+	assert.NoError(t, err)
+	require.Len(t, got, len(c.TestUsers))
+	assert.Equal(t, userName{ID: "u2", FirstName: "Ann"}, got[2])
+
+	selected, err := c.UnderTest.GetAll(ctx, api.Select("id", "last_name"))
+	assert.NoError(t, err)
+	require.Len(t, selected, len(c.TestUsers))
+	assert.Equal(t, users.Object{ID: "u0", LastName: ptr("Smith")}, selected[0])
+}
+
+func CaseGetPageAsProjection(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+
+	got, err := sc.GetPageAs[userName](ctx, c.UnderTest,
+		api.WithPageNumber(1), api.WithPageSize(2))
+	assert.NoError(t, err)
+	assert.Equal(t, api.Page[userName]{
+		Number:     1,
+		Size:       2,
+		Total:      5,
+		TotalPages: 3,
+		Content: []userName{
+			{ID: "u2", FirstName: "Ann"},
+			{ID: "u3", FirstName: "Ann"},
+		},
+	}, got)
+}
+
 func CaseCountByLastNameExists(t *testing.T, params TestCaseParams) {
 	c, ctx := newTestCase(t, params)
 
@@ -237,6 +310,120 @@ func CaseCountByLastNameExists(t *testing.T, params TestCaseParams) {
 	}, got)
 }
 
+func CaseGetPageWithLastName(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+
+	got, err := c.UnderTest.GetPageWithLastName(ctx,
+		api.WithPageNumber(1), api.WithPageSize(2), api.WithSortBy("first_name"), api.WithSortBy("id"))
+	assert.NoError(t, err)
+	assert.Equal(t, api.Page[users.Object]{
+		Number:     1,
+		Size:       1,
+		Total:      3,
+		TotalPages: 2,
+		Content:    []users.Object{c.TestUsers[0]},
+	}, got)
+}
+
+func CaseGetPageKeyset(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+
+	gotPage1, err := c.UnderTest.GetPage(ctx,
+		api.WithPageSize(2), api.WithSortBy("first_name"), api.WithSortBy("id"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Ann Stone\nAnn Brick", usersToString(gotPage1.Content...))
+
+	last := gotPage1.Content[len(gotPage1.Content)-1]
+	gotPage2, err := c.UnderTest.GetPage(ctx,
+		api.WithPageSize(2), api.WithSortBy("first_name"), api.WithSortBy("id"),
+		api.WithAfter(last.FirstName, last.ID))
+	assert.NoError(t, err)
+	assert.Equal(t, "Antony\nCarl", usersToString(gotPage2.Content...))
+	assert.Equal(t, uint64(5), gotPage2.Total)
+}
+
+func CaseFindByFirstNamesBornAfter(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+
+	got, err := c.UnderTest.FindByFirstNamesBornAfter(ctx,
+		[]string{"Ann", "Carl", "John"}, newTime("1986-01-01"))
+	assert.NoError(t, err)
+	assert.Equal(t, []users.Object{c.TestUsers[0], c.TestUsers[2]}, got)
+}
+
+func CaseSavepoints(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+	repo := c.UnderTest.WithAmbientTx(true)
+
+	tx, err := repo.Begin(ctx, nil)
+	require.NoError(t, err)
+	txCtx := sc.WithTxContext(ctx, tx.Tx)
+
+	require.NoError(t, tx.Savepoint(txCtx, "before_delete"))
+	_, err = repo.Delete(txCtx, "u0")
+	require.NoError(t, err)
+	require.NoError(t, tx.RollbackTo(txCtx, "before_delete"))
+	require.NoError(t, tx.Release(txCtx, "before_delete"))
+
+	errFailed := fmt.Errorf("failed")
+	err = tx.InTx(txCtx, func(ctx context.Context, _ *sc.Tx) error {
+		if _, err := repo.Delete(ctx, "u1"); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	assert.ErrorIs(t, err, errFailed)
+
+	err = tx.InTx(txCtx, func(ctx context.Context, tx *sc.Tx) error {
+		if _, err := repo.Delete(ctx, "u2"); err != nil {
+			return err
+		}
+		return tx.InTx(ctx, func(ctx context.Context, _ *sc.Tx) error {
+			_, err := repo.Delete(ctx, "u3")
+			return err
+		})
+	})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	got, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []users.Object{c.TestUsers[0], c.TestUsers[1], c.TestUsers[4]}, got)
+}
+
+func CaseVerifySchema(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+
+	assert.NoError(t, c.UnderTest.Verify(ctx))
+}
+
+func CaseGeneratedRepo(t *testing.T, params TestCaseParams) {
+	c, ctx := newTestCase(t, params)
+	repo := users.NewObjectRepo(c.db, params.Driver)
+
+	smiths, err := repo.FindByLastName(ctx, "Smith")
+	require.NoError(t, err)
+	assert.Equal(t, []users.Object{c.TestUsers[0]}, smiths)
+
+	count, err := repo.CountBornAfter(ctx, newTime("1986-01-01"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	res, err := repo.RenameFirstName(ctx, "Ann", "Anna")
+	require.NoError(t, err)
+	affected, err := res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	all, err := repo.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, len(c.TestUsers))
+
+	page, err := repo.GetPage(ctx, api.WithSortBy(users.ObjectFirstNameColumn))
+	require.NoError(t, err)
+	assert.Equal(t, "Anna", page.Content[0].FirstName)
+}
+
 func createDebugFunc(t *testing.T) api.DebugFunc {
 	return func(query string, args ...any) {
 		t.Logf("query: [%s]; args: %+v\n", query, args)
@@ -0,0 +1,143 @@
+package codegen_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/internal/codegen"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const entitySource = `package accounts
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Audit struct {
+	CreatedAt time.Time ` + "`db:\"created_at\"`" + `
+}
+
+// Account is a customer account.
+//
+//sqlcredo:repo table=accounts id=account_id
+type Account struct {
+	ID       uuid.UUID ` + "`db:\"account_id\"`" + `
+	Email    string
+	Nickname *string ` + "`db:\"nickname\"`" + `
+	secret   string
+	Ignored  string ` + "`db:\"-\"`" + `
+	Audit
+}
+`
+
+const queriesSource = `-- Queries of accounts.
+
+-- name: FindByEmail :one
+-- FindByEmail returns the account with the email.
+-- param: email string
+SELECT * FROM accounts WHERE email = :email
+
+-- name: CreatedSince :many
+-- param: since time.Time
+-- param: type string
+-- result: AccountSummary
+SELECT account_id, email FROM accounts
+WHERE created_at > :since AND type = :type
+
+-- name: Purge :exec
+DELETE FROM accounts
+`
+
+func TestParseEntity(t *testing.T) {
+	dir := writeEntity(t)
+
+	entity, err := codegen.ParseEntity(dir, "Account")
+
+	require.NoError(t, err)
+	assert.Equal(t, "accounts", entity.Package)
+	assert.Equal(t, []codegen.Field{
+		{Name: "ID", Column: "account_id", Type: "uuid.UUID"},
+		{Name: "Email", Column: "email", Type: "string"},
+		{Name: "Nickname", Column: "nickname", Type: "*string"},
+		{Name: "CreatedAt", Column: "created_at", Type: "time.Time"},
+	}, entity.Fields)
+	assert.Equal(t, map[string]string{"table": "accounts", "id": "account_id"}, entity.Annotation)
+	assert.Equal(t, "github.com/google/uuid", entity.Imports["uuid"])
+
+	_, err = codegen.ParseEntity(dir, "Missing")
+	assert.Error(t, err)
+}
+
+func TestParseQueries(t *testing.T) {
+	queries, err := codegen.ParseQueries(strings.NewReader(queriesSource))
+
+	require.NoError(t, err)
+	require.Len(t, queries, 3)
+	assert.Equal(t, codegen.Query{
+		Name:   "FindByEmail",
+		Mode:   codegen.ModeOne,
+		Params: []codegen.Param{{Name: "email", Type: "string"}},
+		Doc:    []string{"FindByEmail returns the account with the email."},
+		SQL:    "SELECT * FROM accounts WHERE email = :email",
+	}, queries[0])
+	assert.Equal(t, "AccountSummary", queries[1].Result)
+	assert.Equal(t, "SELECT account_id, email FROM accounts\nWHERE created_at > :since AND type = :type", queries[1].SQL)
+	assert.Equal(t, codegen.ModeExec, queries[2].Mode)
+}
+
+func TestParseQueries_Invalid(t *testing.T) {
+	_, err := codegen.ParseQueries(strings.NewReader("SELECT 1"))
+	assert.Error(t, err)
+
+	_, err = codegen.ParseQueries(strings.NewReader("-- name: A :one\n-- name: B :one\nSELECT 1"))
+	assert.ErrorContains(t, err, "query A has no SQL")
+}
+
+func TestGenerate(t *testing.T) {
+	entity, err := codegen.ParseEntity(writeEntity(t), "Account")
+	require.NoError(t, err)
+	queries, err := codegen.ParseQueries(strings.NewReader(queriesSource))
+	require.NoError(t, err)
+
+	src, err := codegen.Generate(codegen.Config{
+		Entity:   entity,
+		Table:    "accounts",
+		IDColumn: "account_id",
+		Queries:  queries,
+	})
+
+	require.NoError(t, err)
+	code := string(src)
+	assert.Contains(t, code, `"github.com/google/uuid"`)
+	assert.Contains(t, code, `AccountEmailColumn     = "email"`)
+	assert.Contains(t, code, "sqlcredo.SQLCredo[Account, uuid.UUID]")
+	assert.Contains(t, code, "func (r *AccountRepo) FindByEmail(ctx context.Context, email string) (Account, error)")
+	assert.Contains(t, code,
+		"func (r *AccountRepo) CreatedSince(ctx context.Context, since time.Time, typeParam string) ([]AccountSummary, error)")
+	assert.Contains(t, code, `"type":  typeParam,`)
+	assert.Contains(t, code, "res, err := r.Exec(ctx, accountRepoPurgeQuery)")
+}
+
+func TestGenerate_UnknownIDColumn(t *testing.T) {
+	entity, err := codegen.ParseEntity(writeEntity(t), "Account")
+	require.NoError(t, err)
+
+	_, err = codegen.Generate(codegen.Config{Entity: entity, Table: "accounts", IDColumn: "id"})
+
+	assert.ErrorContains(t, err, `no field of Account is mapped to id column "id"`)
+}
+
+func writeEntity(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "account.go"), []byte(entitySource), 0o600))
+	return dir
+}
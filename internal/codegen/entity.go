@@ -0,0 +1,197 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// directive annotates an entity struct with generator settings, e.g.
+//
+//	//sqlcredo:repo table=users id=id queries=queries.sql
+const directive = "//sqlcredo:repo"
+
+// Entity is a struct type parsed from the source of a package.
+type Entity struct {
+	Package    string
+	Name       string
+	Fields     []Field
+	Annotation map[string]string // Settings of the sqlcredo:repo directive
+	Imports    map[string]string // Import paths of the package files by name
+}
+
+// Field is a column-mapped field of an entity.
+type Field struct {
+	Name   string
+	Column string
+	Type   string // Go type expression, e.g. "*string" or "time.Time"
+}
+
+// FieldByColumn returns the field mapped to column.
+func (e Entity) FieldByColumn(column string) (Field, bool) {
+	for _, f := range e.Fields {
+		if f.Column == column {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// ParseEntity parses the struct typeName from the non-test Go files of dir.
+// Columns follow the sqlx conventions of SQLCredo: the db tag names the column,
+// untagged fields use the lowercased field name, and untagged embedded structs
+// of the same package are flattened.
+func ParseEntity(dir string, typeName string) (Entity, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return Entity{}, fmt.Errorf("unable to list go files: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	structs := map[string]*ast.StructType{}
+	entity := Entity{Imports: map[string]string{}}
+	var doc *ast.CommentGroup
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return Entity{}, fmt.Errorf("unable to parse %s: %w", path, err)
+		}
+		entity.Package = file.Name.Name
+		collectImports(file, entity.Imports)
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				structs[ts.Name.Name] = st
+				if ts.Name.Name == typeName {
+					doc = ts.Doc
+					if doc == nil {
+						doc = gen.Doc
+					}
+				}
+			}
+		}
+	}
+
+	st, ok := structs[typeName]
+	if !ok {
+		return Entity{}, fmt.Errorf("struct %s not found in %s", typeName, dir)
+	}
+
+	entity.Name = typeName
+	entity.Fields = collectFields(st, structs, map[string]bool{typeName: true})
+	entity.Annotation = parseAnnotation(doc)
+	return entity, nil
+}
+
+func collectFields(st *ast.StructType, structs map[string]*ast.StructType, seen map[string]bool) []Field {
+	var fields []Field
+	for _, f := range st.Fields.List {
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			if unquoted, err := strconv.Unquote(f.Tag.Value); err == nil {
+				tag = reflect.StructTag(unquoted)
+			}
+		}
+		dbTag, hasTag := tag.Lookup("db")
+		column, _, _ := strings.Cut(dbTag, ",")
+		if column == "-" {
+			continue
+		}
+
+		if len(f.Names) == 0 {
+			// embedded struct of the same package
+			name, ok := embeddedName(f.Type)
+			if embedded, found := structs[name]; ok && found && !hasTag && !seen[name] {
+				seen[name] = true
+				fields = append(fields, collectFields(embedded, structs, seen)...)
+			}
+			continue
+		}
+
+		for _, n := range f.Names {
+			if !n.IsExported() {
+				continue
+			}
+			c := column
+			if c == "" {
+				c = strings.ToLower(n.Name)
+			}
+			fields = append(fields, Field{Name: n.Name, Column: c, Type: types.ExprString(f.Type)})
+		}
+	}
+	return fields
+}
+
+func embeddedName(expr ast.Expr) (string, bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	return ident.Name, true
+}
+
+func collectImports(file *ast.File, imports map[string]string) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+}
+
+func parseAnnotation(doc *ast.CommentGroup) map[string]string {
+	res := map[string]string{}
+	if doc == nil {
+		return res
+	}
+	for _, c := range doc.List {
+		settings, ok := strings.CutPrefix(c.Text, directive)
+		if !ok {
+			continue
+		}
+		for _, s := range strings.Fields(settings) {
+			key, value, _ := strings.Cut(s, "=")
+			res[key] = value
+		}
+	}
+	return res
+}
+
+// importName returns the default name of an import path, skipping major version suffixes.
+func importName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = parts[len(parts)-2]
+		}
+	}
+	return name
+}
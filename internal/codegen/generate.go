@@ -0,0 +1,254 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Config describes the repository to generate.
+type Config struct {
+	Entity   Entity
+	Table    string
+	IDColumn string
+	Repo     string // Name of the repository type, <Entity>Repo by default
+	Queries  []Query
+}
+
+// Generate returns the formatted source of the repository: column constants,
+// a struct embedding SQLCredo, its constructor and a typed method per query.
+func Generate(cfg Config) ([]byte, error) {
+	if cfg.Repo == "" {
+		cfg.Repo = cfg.Entity.Name + "Repo"
+	}
+	idField, ok := cfg.Entity.FieldByColumn(cfg.IDColumn)
+	if !ok {
+		return nil, fmt.Errorf("no field of %s is mapped to id column %q", cfg.Entity.Name, cfg.IDColumn)
+	}
+
+	data := templateData{
+		Config:  cfg,
+		IDType:  idField.Type,
+		IDConst: columnConst(cfg.Entity.Name, idField),
+	}
+	for _, f := range cfg.Entity.Fields {
+		data.Columns = append(data.Columns, column{Const: columnConst(cfg.Entity.Name, f), Name: f.Column})
+	}
+
+	typeExprs := []string{idField.Type}
+	for _, q := range cfg.Queries {
+		m := method{
+			Query:      q,
+			QueryConst: lowerFirst(cfg.Repo) + q.Name + "Query",
+			Result:     q.Result,
+		}
+		if m.Result == "" {
+			m.Result = cfg.Entity.Name
+		}
+		if len(m.Doc) == 0 {
+			m.Doc = []string{fmt.Sprintf("%s runs the %s query.", q.Name, q.Name)}
+		}
+		for _, p := range q.Params {
+			m.Args = append(m.Args, arg{Name: argName(p.Name), Param: p.Name, Type: p.Type})
+			typeExprs = append(typeExprs, p.Type)
+		}
+		typeExprs = append(typeExprs, m.Result)
+		data.Methods = append(data.Methods, m)
+	}
+
+	imports, err := resolveImports(typeExprs, cfg.Entity.Imports)
+	if err != nil {
+		return nil, err
+	}
+	data.Imports = append([]string{`"database/sql"`}, imports...)
+	if len(cfg.Queries) > 0 {
+		data.Imports = append(data.Imports, `"context"`, `"fmt"`)
+	}
+	data.Imports = dedupeSorted(data.Imports)
+
+	var buf bytes.Buffer
+	if err := repoTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("unable to execute template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format generated code: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
+
+type templateData struct {
+	Config
+	Imports []string
+	IDType  string
+	IDConst string
+	Columns []column
+	Methods []method
+}
+
+type column struct {
+	Const string
+	Name  string
+}
+
+type method struct {
+	Query
+	QueryConst string
+	Result     string
+	Args       []arg
+}
+
+type arg struct {
+	Name  string
+	Param string
+	Type  string
+}
+
+var repoTemplate = template.Must(template.New("repo").Funcs(template.FuncMap{
+	"backquote": func(s string) string { return "`" + strings.ReplaceAll(s, "`", "` + \"`\" + `") + "`" },
+}).Parse(`// Code generated by sqlcredo-gen. DO NOT EDIT.
+
+package {{.Entity.Package}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+
+	"github.com/Klojer/sqlcredo"
+)
+
+// Columns of {{.Entity.Name}} in the {{.Table}} table.
+const (
+{{- range .Columns}}
+	{{.Const}} = "{{.Name}}"
+{{- end}}
+)
+
+// {{.Repo}} is the repository of {{.Entity.Name}} records in the {{.Table}} table.
+type {{.Repo}} struct {
+	sqlcredo.SQLCredo[{{.Entity.Name}}, {{.IDType}}]
+}
+
+// New{{.Repo}} creates the repository on db opened with driver.
+func New{{.Repo}}(db *sql.DB, driver string) *{{.Repo}} {
+	return &{{.Repo}}{
+		SQLCredo: sqlcredo.NewSQLCredo[{{.Entity.Name}}, {{.IDType}}](db, driver, "{{.Table}}", {{.IDConst}}),
+	}
+}
+{{range .Methods}}
+const {{.QueryConst}} = {{backquote .SQL}}
+{{range .Doc}}
+// {{.}}
+{{- end}}
+func (r *{{$.Repo}}) {{.Name}}(ctx context.Context{{range .Args}}, {{.Name}} {{.Type}}{{end}}) (
+{{- if eq .Mode "one"}}{{.Result}}{{else if eq .Mode "many"}}[]{{.Result}}{{else}}sql.Result{{end}}, error) {
+{{- if eq .Mode "exec"}}
+	res, err := r.{{if .Args}}NamedExec{{else}}Exec{{end}}(ctx, {{.QueryConst}}{{template "args" .}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	return res, nil
+{{- else if eq .Mode "one"}}
+	var res {{.Result}}
+	if err := r.{{if .Args}}NamedSelectOne{{else}}SelectOne{{end}}(ctx, &res, {{.QueryConst}}{{template "args" .}}); err != nil {
+		return res, fmt.Errorf("unable to select record: %w", err)
+	}
+	return res, nil
+{{- else}}
+	var res []{{.Result}}
+	if err := r.{{if .Args}}NamedSelectMany{{else}}SelectMany{{end}}(ctx, &res, {{.QueryConst}}{{template "args" .}}); err != nil {
+		return nil, fmt.Errorf("unable to select records: %w", err)
+	}
+	return res, nil
+{{- end}}
+}
+{{end}}
+{{- define "args"}}{{if .Args}}, map[string]any{
+{{- range .Args}}
+		"{{.Param}}": {{.Name}},
+{{- end}}
+	}{{end}}{{end}}
+`))
+
+// columnConst names the constant of a column, e.g. UserFirstNameColumn.
+func columnConst(entity string, f Field) string {
+	return entity + f.Name + "Column"
+}
+
+// reserved are identifiers of the generated methods that arguments must not shadow.
+var reserved = map[string]bool{"ctx": true, "r": true, "res": true, "err": true, "sql": true, "fmt": true, "context": true}
+
+// argName converts a snake_case parameter name to a camelCase argument name.
+func argName(param string) string {
+	parts := strings.Split(param, "_")
+	var b strings.Builder
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString(strings.ToLower(p[:1]) + p[1:])
+		} else {
+			b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+		}
+	}
+	name := b.String()
+	if token.IsKeyword(name) || reserved[name] {
+		name += "Param"
+	}
+	return name
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+var qualifier = regexp.MustCompile(`\b([A-Za-z_]\w*)\.`)
+
+// resolveImports returns the import specs of the packages referenced by type expressions,
+// looked up in the imports of the entity package; unknown names are assumed to be
+// standard library packages, e.g. "time".
+func resolveImports(typeExprs []string, known map[string]string) ([]string, error) {
+	var res []string
+	for _, t := range typeExprs {
+		for _, m := range qualifier.FindAllStringSubmatch(t, -1) {
+			if path, ok := known[m[1]]; ok {
+				spec := strconv.Quote(path)
+				if importName(path) != m[1] {
+					spec = m[1] + " " + spec
+				}
+				res = append(res, spec)
+				continue
+			}
+			if strings.ContainsAny(m[1], "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+				return nil, fmt.Errorf("unable to resolve package %s of type %s", m[1], t)
+			}
+			res = append(res, strconv.Quote(m[1]))
+		}
+	}
+	return res, nil
+}
+
+func dedupeSorted(values []string) []string {
+	sort.Strings(values)
+	res := values[:0]
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,108 @@
+package codegen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Query modes.
+const (
+	ModeOne  = "one"  // Returns a single record
+	ModeMany = "many" // Returns a slice of records
+	ModeExec = "exec" // Returns sql.Result
+)
+
+// Query is a named query of a .sql file.
+type Query struct {
+	Name   string
+	Mode   string
+	Result string   // Record type, the entity if empty
+	Params []Param  // Named parameters in declaration order
+	Doc    []string // Comment lines describing the query
+	SQL    string
+}
+
+// Param is a named parameter of a query, referenced as :name in its SQL.
+type Param struct {
+	Name string
+	Type string
+}
+
+var (
+	nameLine   = regexp.MustCompile(`^--\s*name:\s*(\w+)\s+:(one|many|exec)\s*$`)
+	paramLine  = regexp.MustCompile(`^--\s*param:\s*(\w+)\s+(\S+)\s*$`)
+	resultLine = regexp.MustCompile(`^--\s*result:\s*(\S+)\s*$`)
+)
+
+// ParseQueries parses named queries. Each query starts with a name line
+// followed by optional parameter, result and comment lines:
+//
+//	-- name: FindByLastName :many
+//	-- FindByLastName returns the users with the given last name.
+//	-- param: last_name string
+//	SELECT * FROM users WHERE last_name = :last_name
+func ParseQueries(r io.Reader) ([]Query, error) {
+	var queries []Query
+	var sql strings.Builder
+	lineNo := 0
+
+	flush := func() {
+		if len(queries) > 0 {
+			queries[len(queries)-1].SQL = strings.TrimSpace(sql.String())
+		}
+		sql.Reset()
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if m := nameLine.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			queries = append(queries, Query{Name: m[1], Mode: m[2]})
+			continue
+		}
+		if len(queries) == 0 {
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return nil, fmt.Errorf("line %d: SQL before the first '-- name:' line", lineNo)
+			}
+			continue
+		}
+
+		q := &queries[len(queries)-1]
+		inHeader := sql.Len() == 0
+		switch m := paramLine.FindStringSubmatch(trimmed); {
+		case inHeader && m != nil:
+			q.Params = append(q.Params, Param{Name: m[1], Type: m[2]})
+		case inHeader && resultLine.MatchString(trimmed):
+			q.Result = resultLine.FindStringSubmatch(trimmed)[1]
+		case inHeader && strings.HasPrefix(trimmed, "--"):
+			q.Doc = append(q.Doc, strings.TrimSpace(strings.TrimPrefix(trimmed, "--")))
+		case inHeader && trimmed == "":
+		default:
+			sql.WriteString(line)
+			sql.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read queries: %w", err)
+	}
+	flush()
+
+	seen := map[string]bool{}
+	for _, q := range queries {
+		if q.SQL == "" {
+			return nil, fmt.Errorf("query %s has no SQL", q.Name)
+		}
+		if seen[q.Name] {
+			return nil, fmt.Errorf("duplicate query %s", q.Name)
+		}
+		seen[q.Name] = true
+	}
+	return queries, nil
+}
@@ -4,24 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 
 	"github.com/Klojer/sqlcredo/internal/goquext"
+	"github.com/Klojer/sqlcredo/internal/structmap"
 	"github.com/Klojer/sqlcredo/internal/table"
 	"github.com/Klojer/sqlcredo/pkg/api"
 
 	"github.com/doug-martin/goqu/v9"
 )
 
-const (
-	truncateQueryTemplateSqlite3 = "DELETE FROM %s;"
-	truncateQueryTemplateDefault = "TRUNCATE %s;"
-)
-
 type CRUD[T any, I comparable] struct {
-	table         table.Info
-	executor      api.SQLExecutor
+	*Reader[T, I]
+	driver        string
+	upsertDialect goqu.DialectWrapper
 	truncateQuery string
-	dialect       goqu.DialectWrapper
+	truncateErr   error
 }
 
 var _ api.CRUD[any, string] = &CRUD[any, string]{}
@@ -29,111 +27,244 @@ var _ api.CRUD[any, string] = &CRUD[any, string]{}
 func NewCRUD[T any, I comparable](table table.Info,
 	executor api.SQLExecutor, driver string,
 ) *CRUD[T, I] {
-	return &CRUD[T, I]{
-		table:         table,
-		executor:      executor,
-		truncateQuery: createTruncateQuery(driver, table.Name),
-		dialect:       goqu.Dialect(goquext.CreateDialectString(driver)),
+	r := &CRUD[T, I]{
+		Reader:        NewReader[T, I](table, executor, driver),
+		driver:        driver,
+		upsertDialect: goqu.Dialect(goquext.CreateUpsertDialectString(driver)),
 	}
+	r.SetTable(table)
+	return r
 }
 
-func (r *CRUD[T, I]) GetAll(ctx context.Context) ([]T, error) {
-	query, args, err := r.dialect.From(r.table.Name).Prepared(true).ToSQL()
-	if err != nil {
-		return nil, fmt.Errorf("unable to create 'select all' query: %w", err)
+// SetTable replaces the table configuration, e.g. to set its schema or tenant scoping.
+func (r *CRUD[T, I]) SetTable(table table.Info) {
+	r.Reader.SetTable(table)
+	if table.Static() {
+		// a failure is reported by DeleteAll
+		r.truncateQuery, r.truncateErr = r.buildTruncateQuery(context.Background())
 	}
-	return r.selectMany(ctx, query, args...)
 }
 
-func (r *CRUD[T, I]) GetByID(ctx context.Context, id I) (T, error) {
-	var record T
+func (r *CRUD[T, I]) Create(ctx context.Context, e *T) (sql.Result, error) {
+	if err := r.fillTenant(ctx, e); err != nil {
+		return nil, err
+	}
+
+	if q := r.queries.insert; q != nil && r.table.Static() {
+		return r.executor.Exec(ctx, q.sql, q.args(reflect.ValueOf(e).Elem(), nil)...)
+	}
 
-	query, args, err := r.dialect.From(r.table.Name).
-		Where(goqu.I(r.table.IDColumn).Eq(id)).
+	query, args, err := r.dialect.Insert(r.table.Table(ctx)).
+		Rows(e).
 		Prepared(true).
 		ToSQL()
 	if err != nil {
-		return record, fmt.Errorf("unable to create 'select by id' query: %w", err)
+		return nil, fmt.Errorf("unable to create 'insert' query: %w", err)
 	}
 
-	err = r.executor.SelectOne(ctx, &record, query, args...)
-	if err != nil {
-		return record, fmt.Errorf("unable to select record: %w", err)
+	return r.executor.Exec(ctx, query, args...)
+}
+
+func (r *CRUD[T, I]) DeleteAll(ctx context.Context) (sql.Result, error) {
+	if !r.table.Tenant.Enabled() {
+		query, err := r.truncateQuery, r.truncateErr
+		if !r.table.Static() {
+			query, err = r.buildTruncateQuery(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to create 'truncate' query: %w", err)
+		}
+		return r.executor.Exec(ctx, query)
 	}
 
-	return record, nil
-}
+	where, err := r.where(ctx, api.Filter{})
+	if err != nil {
+		return nil, err
+	}
 
-func (r *CRUD[T, I]) GetByIDs(ctx context.Context, ids []I) ([]T, error) {
-	query, args, err := r.dialect.From(r.table.Name).
-		Where(goqu.I(r.table.IDColumn).In(ids)).
-		Order(goqu.I(r.table.IDColumn).Asc()).
+	query, args, err := r.dialect.Delete(r.table.Table(ctx)).
+		Where(where).
 		Prepared(true).
 		ToSQL()
 	if err != nil {
-		return nil, fmt.Errorf("unable to create 'select by ids' query: %w", err)
+		return nil, fmt.Errorf("unable to create 'delete all' query: %w", err)
 	}
 
-	entities, err := r.selectMany(ctx, query, args...)
+	return r.executor.Exec(ctx, query, args...)
+}
+
+func (r *CRUD[T, I]) Delete(ctx context.Context, id I) (sql.Result, error) {
+	if q := r.queries.deleteByID; q != nil && r.usePrecomputed() {
+		return r.executor.Exec(ctx, q.sql, q.args(reflect.Value{}, id)...)
+	}
+
+	where, err := r.where(ctx, api.Eq(r.table.IDColumn, id))
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.dialect.Delete(r.table.Table(ctx)).
+		Where(where).
+		Prepared(true).
+		ToSQL()
 	if err != nil {
-		return nil, fmt.Errorf("unable to select records: %w", err)
+		return nil, fmt.Errorf("unable to create 'delete' query: %w", err)
 	}
 
-	return entities, nil
+	return r.executor.Exec(ctx, query, args...)
 }
 
-func (r *CRUD[T, I]) Create(ctx context.Context, e *T) (sql.Result, error) {
-	query, args, err := r.dialect.Insert(r.table.Name).
-		Rows(e).
+func (r *CRUD[T, I]) Update(ctx context.Context, id I, e *T) (sql.Result, error) {
+	if q := r.queries.update; q != nil && r.usePrecomputed() {
+		return r.executor.Exec(ctx, q.sql, q.args(reflect.ValueOf(e).Elem(), id)...)
+	}
+
+	// the tenant column is overwritten, so records can't be moved to another tenant
+	if err := r.fillTenant(ctx, e); err != nil {
+		return nil, err
+	}
+	where, err := r.where(ctx, api.Eq(r.table.IDColumn, id))
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.dialect.Update(r.table.Table(ctx)).
+		Set(*e).
+		Where(where).
 		Prepared(true).
 		ToSQL()
 	if err != nil {
-		return nil, fmt.Errorf("unable to create 'insert' query: %w", err)
+		return nil, fmt.Errorf("unable to create 'update' query: %w", err)
 	}
 
 	return r.executor.Exec(ctx, query, args...)
 }
 
-func (r *CRUD[T, I]) DeleteAll(ctx context.Context) (sql.Result, error) {
-	return r.executor.Exec(ctx, r.truncateQuery)
-}
+// Upsert inserts e or updates its columns if a record with its ID exists.
+// Columns tagged goqu:"skipupdate" or goqu:"skipinsert" keep their stored values.
+// With tenant scoping, records of other tenants are left unchanged.
+func (r *CRUD[T, I]) Upsert(ctx context.Context, e *T) (sql.Result, error) {
+	if err := r.fillTenant(ctx, e); err != nil {
+		return nil, err
+	}
 
-func (r *CRUD[T, I]) Delete(ctx context.Context, id I) (sql.Result, error) {
-	query, args, err := r.dialect.Delete(r.table.Name).
-		Where(goqu.I(r.table.IDColumn).Eq(id)).
+	tenant := r.table.Tenant
+	set := goqu.Record{}
+	for _, f := range structmap.Fields(r.entityType) {
+		c := f.Column
+		if c == r.table.IDColumn || (tenant.Enabled() && c == tenant.Column) {
+			continue
+		}
+		// skipinsert columns hold the database default in excluded
+		if hasGoquOption(f, "skipupdate") || hasGoquOption(f, "skipinsert") {
+			continue
+		}
+		set[c] = goqu.L("excluded.?", goqu.I(c))
+	}
+
+	conflict := goqu.DoNothing()
+	if len(set) > 0 {
+		update := goqu.DoUpdate(r.table.IDColumn, set)
+		if tenant.Enabled() {
+			value, err := tenant.Value(ctx)
+			if err != nil {
+				return nil, err
+			}
+			update = update.Where(r.table.Table(ctx).Col(tenant.Column).Eq(value))
+		}
+		conflict = update
+	}
+
+	query, args, err := r.upsertDialect.Insert(r.table.Table(ctx)).
+		Rows(e).
+		OnConflict(conflict).
 		Prepared(true).
 		ToSQL()
 	if err != nil {
-		return nil, fmt.Errorf("unable to create 'delete' query: %w", err)
+		return nil, fmt.Errorf("unable to create 'upsert' query: %w", err)
 	}
 
 	return r.executor.Exec(ctx, query, args...)
 }
 
-func (r *CRUD[T, I]) Update(ctx context.Context, id I, e *T) (sql.Result, error) {
-	query, args, err := r.dialect.Update(r.table.Name).
-		Set(*e).
-		Where(goqu.I(r.table.IDColumn).Eq(id)).
+// UpdateColumns sets the given columns of the record identified by id.
+func (r *CRUD[T, I]) UpdateColumns(ctx context.Context, id I, values map[string]any) (sql.Result, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("unable to update columns: no values")
+	}
+	if tenant := r.table.Tenant; tenant.Enabled() {
+		if _, ok := values[tenant.Column]; ok {
+			return nil, fmt.Errorf("unable to update columns: tenant column %q can't be changed", tenant.Column)
+		}
+	}
+
+	where, err := r.where(ctx, api.Eq(r.table.IDColumn, id))
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.dialect.Update(r.table.Table(ctx)).
+		Set(goqu.Record(values)).
+		Where(where).
 		Prepared(true).
 		ToSQL()
 	if err != nil {
-		return nil, fmt.Errorf("unable to create 'update' query: %w", err)
+		return nil, fmt.Errorf("unable to create 'update columns' query: %w", err)
 	}
 
 	return r.executor.Exec(ctx, query, args...)
 }
 
-func (r *CRUD[T, I]) selectMany(ctx context.Context, query string, args ...any) ([]T, error) {
-	var records []T
-	if err := r.executor.SelectMany(ctx, &records, query, args...); err != nil {
-		return nil, fmt.Errorf("unable to load records: %w", err)
+// fillTenant sets the tenant field of e to the tenant of ctx.
+func (r *CRUD[T, I]) fillTenant(ctx context.Context, e *T) error {
+	tenant := r.table.Tenant
+	if !tenant.Enabled() {
+		return nil
+	}
+
+	value, err := tenant.Value(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, ok := structmap.ByColumn(r.entityType, tenant.Column)
+	if !ok {
+		return fmt.Errorf("unable to set tenant: no field for column %q in %s", tenant.Column, r.entityType)
+	}
+	field, err := reflect.ValueOf(e).Elem().FieldByIndexErr(f.Index)
+	if err != nil {
+		return fmt.Errorf("unable to set tenant: %w", err)
+	}
+
+	// convertible values are converted, e.g. string to a custom ID type or int to int64
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !v.CanConvert(field.Type()) || isNumberToString(v.Type(), field.Type()) {
+		return fmt.Errorf("unable to set tenant: %T is not convertible to %s", value, field.Type())
+	}
+	field.Set(v.Convert(field.Type()))
+	return nil
+}
+
+// isNumberToString reports whether converting from to to would turn a number
+// into the string of a rune rather than its digits.
+func isNumberToString(from, to reflect.Type) bool {
+	if to.Kind() != reflect.String {
+		return false
+	}
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
 	}
-	return records, nil
+	return false
 }
 
-func createTruncateQuery(driver string, table string) string {
-	if driver == "sqlite3" {
-		return fmt.Sprintf(truncateQueryTemplateSqlite3, table)
+// buildTruncateQuery builds the query deleting all records; sqlite has no TRUNCATE.
+func (r *CRUD[T, I]) buildTruncateQuery(ctx context.Context) (string, error) {
+	if r.driver == "sqlite3" {
+		query, _, err := r.dialect.Delete(r.table.Table(ctx)).ToSQL()
+		return query, err
 	}
-	return fmt.Sprintf(truncateQueryTemplateDefault, table)
+	query, _, err := r.dialect.Truncate(r.table.Table(ctx)).ToSQL()
+	return query, err
 }
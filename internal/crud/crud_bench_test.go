@@ -0,0 +1,134 @@
+package crud_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/internal/crud"
+	"github.com/Klojer/sqlcredo/internal/table"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// BenchmarkCRUD compares the precomputed queries against building the same
+// statements through goqu on every call.
+func BenchmarkCRUD(b *testing.B) {
+	ctx := context.Background()
+	tableInfo := table.Info{Name: "bench_table", IDColumn: "id"}
+	underTest := crud.NewCRUD[benchObj, int64](tableInfo, nopExecutor{}, "postgres")
+	dialect := goqu.Dialect("postgres")
+	e := &benchObj{ID: 1, Name: "name", Email: "email", Age: 42}
+
+	b.Run("GetByID/precomputed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := underTest.GetByID(ctx, 1); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetByID/builder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, err := dialect.From(tableInfo.Name).
+				Select(goqu.I("id"), goqu.I("name"), goqu.I("email"), goqu.I("age")).
+				Where(goqu.I(tableInfo.IDColumn).Eq(int64(1))).
+				Prepared(true).
+				ToSQL()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Create/precomputed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := underTest.Create(ctx, e); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Create/builder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, err := dialect.Insert(tableInfo.Name).Rows(e).Prepared(true).ToSQL()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Update/precomputed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := underTest.Update(ctx, 1, e); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Update/builder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, err := dialect.Update(tableInfo.Name).
+				Set(*e).
+				Where(goqu.I(tableInfo.IDColumn).Eq(int64(1))).
+				Prepared(true).
+				ToSQL()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Delete/precomputed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := underTest.Delete(ctx, 1); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Delete/builder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, err := dialect.Delete(tableInfo.Name).
+				Where(goqu.I(tableInfo.IDColumn).Eq(int64(1))).
+				Prepared(true).
+				ToSQL()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+type benchObj struct {
+	ID    int64  `db:"id"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+	Age   int    `db:"age"`
+}
+
+// nopExecutor discards all statements, so the benchmarks measure query building only.
+type nopExecutor struct{}
+
+var _ api.SQLExecutor = nopExecutor{}
+
+func (nopExecutor) Exec(context.Context, string, ...any) (sql.Result, error) {
+	return nil, nil
+}
+
+func (nopExecutor) SelectOne(context.Context, any, string, ...any) error {
+	return nil
+}
+
+func (nopExecutor) SelectMany(context.Context, any, string, ...any) error {
+	return nil
+}
+
+func (nopExecutor) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, nil
+}
@@ -17,7 +17,7 @@ import (
 
 func TestCRUD_GetAll(t *testing.T) {
 	c, ctx := newTestCase(t)
-	c.Executor.On("SelectMany", ctx, mock.Anything, "SELECT * FROM `test_table`", mock.Anything).
+	c.Executor.On("SelectMany", ctx, mock.Anything, "SELECT `id`, `name` FROM `test_table`", mock.Anything).
 		Return(nil)
 
 	_, err := c.UnderTest.GetAll(ctx)
@@ -25,10 +25,90 @@ func TestCRUD_GetAll(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCRUD_GetAll_Select(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectMany", ctx, mock.Anything, "SELECT `name` FROM `test_table`", mock.Anything).
+		Return(nil)
+
+	_, err := c.UnderTest.GetAll(ctx, api.Select("name"))
+
+	assert.NoError(t, err)
+}
+
+func TestCRUD_GetAll_Where(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectMany", ctx, mock.Anything,
+		"SELECT `id`, `name` FROM `test_table` WHERE (`name` IN (?, ?))", []any{"a", "b"}).
+		Return(nil)
+
+	_, err := c.UnderTest.GetAll(ctx, api.Where(api.In("name", "a", "b")))
+
+	assert.NoError(t, err)
+}
+
+func TestCRUD_GetAll_MaxRows(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.(*crud.CRUD[testObj, string]).SetMaxRows(1)
+	c.Executor.On("SelectMany", ctx, mock.Anything, "SELECT `id`, `name` FROM `test_table` LIMIT ?", []any{int64(2)}).
+		Run(func(args mock.Arguments) {
+			dest := args.Get(1).(*[]testObj)
+			*dest = append(*dest, testObj{Id: "1"}, testObj{Id: "2"})
+		}).
+		Return(nil)
+
+	_, err := c.UnderTest.GetAll(ctx)
+
+	assert.ErrorIs(t, err, api.ErrTooManyRows)
+}
+
+func TestCRUD_GetAllInto_MaxRowsWithPrefilledDestination(t *testing.T) {
+	c, ctx := newTestCase(t)
+	reader := c.UnderTest.(*crud.CRUD[testObj, string])
+	reader.SetMaxRows(2)
+	c.Executor.On("SelectMany", ctx, mock.Anything, "SELECT `id`, `name` FROM `test_table` LIMIT ?", []any{int64(3)}).
+		Run(func(args mock.Arguments) {
+			dest := args.Get(1).(*[]testObj)
+			*dest = append(*dest, testObj{Id: "3"}, testObj{Id: "4"})
+		}).
+		Return(nil)
+
+	dest := []testObj{{Id: "1"}, {Id: "2"}}
+	err := reader.GetAllInto(ctx, &dest)
+
+	assert.NoError(t, err)
+	assert.Len(t, dest, 4)
+}
+
+func TestCRUD_GetAllInto(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectMany", ctx, mock.Anything, "SELECT `id` FROM `test_table`", mock.Anything).
+		Return(nil)
+
+	var dest []struct {
+		Id string `db:"id"`
+	}
+	err := c.UnderTest.(*crud.CRUD[testObj, string]).GetAllInto(ctx, &dest)
+
+	assert.NoError(t, err)
+}
+
+func TestCRUD_GetAllInto_InvalidDestination(t *testing.T) {
+	c, ctx := newTestCase(t)
+	reader := c.UnderTest.(*crud.CRUD[testObj, string])
+
+	var one testObj
+	err := reader.GetAllInto(ctx, &one)
+	assert.ErrorContains(t, err, "destination must be a pointer to a slice, got *crud_test.testObj")
+
+	var many []testObj
+	err = reader.GetAllInto(ctx, many)
+	assert.ErrorContains(t, err, "destination must be a pointer to a slice")
+}
+
 func TestCRUD_GetByID(t *testing.T) {
 	c, ctx := newTestCase(t)
 	c.Executor.On("SelectOne", ctx, mock.Anything,
-		"SELECT * FROM `test_table` WHERE (`id` = ?)", []any{"test_id"}).
+		"SELECT `id`, `name` FROM `test_table` WHERE (`id` = ?)", []any{"test_id"}).
 		Return(nil)
 
 	_, err := c.UnderTest.GetByID(ctx, "test_id")
@@ -39,7 +119,7 @@ func TestCRUD_GetByID(t *testing.T) {
 func TestCRUD_GetByID_Error(t *testing.T) {
 	c, ctx := newTestCase(t)
 	c.Executor.On("SelectOne", ctx, mock.Anything,
-		"SELECT * FROM `test_table` WHERE (`id` = ?)", []any{"non_existent_id"}).
+		"SELECT `id`, `name` FROM `test_table` WHERE (`id` = ?)", []any{"non_existent_id"}).
 		Return(fmt.Errorf("database error"))
 
 	_, err := c.UnderTest.GetByID(ctx, "non_existent_id")
@@ -51,7 +131,7 @@ func TestCRUD_GetByID_Error(t *testing.T) {
 func TestCRUD_GetByIDs(t *testing.T) {
 	c, ctx := newTestCase(t)
 	c.Executor.On("SelectMany", ctx, mock.Anything,
-		"SELECT * FROM `test_table` WHERE (`id` IN (?, ?, ?)) ORDER BY `id` ASC",
+		"SELECT `id`, `name` FROM `test_table` WHERE (`id` IN (?, ?, ?)) ORDER BY `id` ASC",
 		[]any{"0", "3", "16"}).
 		Return(nil)
 
@@ -63,7 +143,7 @@ func TestCRUD_GetByIDs(t *testing.T) {
 func TestCRUD_GetByIDs_NoMatch(t *testing.T) {
 	c, ctx := newTestCase(t)
 	c.Executor.On("SelectMany", ctx, mock.Anything,
-		"SELECT * FROM `test_table` WHERE (`id` IN (?, ?)) ORDER BY `id` ASC",
+		"SELECT `id`, `name` FROM `test_table` WHERE (`id` IN (?, ?)) ORDER BY `id` ASC",
 		[]any{"invalid_id_1", "invalid_id_2"}).
 		Return(nil)
 
@@ -73,6 +153,55 @@ func TestCRUD_GetByIDs_NoMatch(t *testing.T) {
 	assert.Empty(t, result)
 }
 
+func TestCRUD_ExistsByID(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectOne", ctx, mock.Anything,
+		"SELECT EXISTS (SELECT 1 FROM `test_table` WHERE (`id` = ?) LIMIT ?)",
+		[]any{"test_id", int64(1)}).
+		Run(func(args mock.Arguments) { *args.Get(1).(*bool) = true }).
+		Return(nil)
+
+	exists, err := c.UnderTest.ExistsByID(ctx, "test_id")
+
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestCRUD_Exists(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectOne", ctx, mock.Anything,
+		"SELECT EXISTS (SELECT 1 FROM `test_table` WHERE ((`name` = ?) AND (`id` != ?)) LIMIT ?)",
+		[]any{"test12", "12", int64(1)}).
+		Run(func(args mock.Arguments) { *args.Get(1).(*bool) = true }).
+		Return(nil)
+
+	exists, err := c.UnderTest.Exists(ctx, api.And(api.Eq("name", "test12"), api.Ne("id", "12")))
+
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestCRUD_Exists_OrWithZeroFilter(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectOne", ctx, mock.Anything,
+		"SELECT EXISTS (SELECT 1 FROM `test_table` LIMIT ?)", []any{int64(1)}).
+		Run(func(args mock.Arguments) { *args.Get(1).(*bool) = false }).
+		Return(nil)
+
+	exists, err := c.UnderTest.Exists(ctx, api.Or(api.Filter{}, api.Eq("name", "test12")))
+
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestCRUD_Exists_InvalidFilter(t *testing.T) {
+	c, ctx := newTestCase(t)
+
+	_, err := c.UnderTest.Exists(ctx, api.Eq("", "test12"))
+
+	assert.ErrorIs(t, err, api.ErrInvalidFilter)
+}
+
 func TestCRUD_Create(t *testing.T) {
 	c, ctx := newTestCase(t)
 	c.Executor.On("Exec", ctx,
@@ -98,7 +227,7 @@ func TestCRUD_Create_Error(t *testing.T) {
 
 func TestCRUD_DeleteAll(t *testing.T) {
 	c, ctx := newTestCase(t)
-	c.Executor.On("Exec", ctx, "DELETE FROM test_table;", mock.Anything).
+	c.Executor.On("Exec", ctx, "DELETE FROM `test_table`", mock.Anything).
 		Return(mocks.NewSQLResult(1, 1), nil)
 
 	_, err := c.UnderTest.DeleteAll(ctx)
@@ -154,6 +283,184 @@ func TestCRUD_Update_NonExistent(t *testing.T) {
 	assert.Contains(t, err.Error(), "update error")
 }
 
+func TestCRUD_Create_SkipInsert(t *testing.T) {
+	ctx := context.Background()
+	executor := mocks.NewSQLExecutor()
+	executor.On("Exec", ctx,
+		"INSERT INTO `test_table` (`name`) VALUES (?)", []any{"test12"}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+	executor.On("Exec", ctx,
+		"UPDATE `test_table` SET `id`=?,`name`=? WHERE (`id` = ?)", []any{int64(12), "test12", int64(12)}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+
+	underTest := crud.NewCRUD[autoIDObj, int64](table.Info{Name: "test_table", IDColumn: "id"},
+		executor, "sqlite3")
+
+	_, err := underTest.Create(ctx, &autoIDObj{Id: 12, Name: "test12"})
+	assert.NoError(t, err)
+	_, err = underTest.Update(ctx, 12, &autoIDObj{Id: 12, Name: "test12"})
+	assert.NoError(t, err)
+	executor.AssertExpectations(t)
+}
+
+func TestCRUD_UpdateColumns(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("Exec", ctx,
+		"UPDATE `test_table` SET `name`=? WHERE (`id` = ?)", []any{"new name", "12"}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+
+	_, err := c.UnderTest.(*crud.CRUD[testObj, string]).
+		UpdateColumns(ctx, "12", map[string]any{"name": "new name"})
+
+	assert.NoError(t, err)
+}
+
+func TestCRUD_UpdateColumns_Empty(t *testing.T) {
+	c, ctx := newTestCase(t)
+
+	_, err := c.UnderTest.(*crud.CRUD[testObj, string]).UpdateColumns(ctx, "12", nil)
+
+	assert.Error(t, err)
+}
+
+func TestCRUD_Upsert(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("Exec", ctx,
+		"INSERT INTO `test_table` (`id`, `name`) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET `name`=excluded.`name`",
+		[]any{"12", "test12"}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+
+	_, err := c.UnderTest.Upsert(ctx, &testObj{Id: "12", Name: "test12"})
+
+	assert.NoError(t, err)
+}
+
+func TestCRUD_Upsert_SkipColumns(t *testing.T) {
+	type taggedObj struct {
+		Id        string `db:"id"`
+		Name      string `db:"name"`
+		CreatedAt string `db:"created_at" goqu:"skipupdate"`
+		Version   int64  `db:"version" goqu:"skipinsert"`
+	}
+
+	ctx := context.Background()
+	executor := mocks.NewSQLExecutor()
+	executor.On("Exec", ctx,
+		"INSERT INTO `test_table` (`created_at`, `id`, `name`) VALUES (?, ?, ?) "+
+			"ON CONFLICT (id) DO UPDATE SET `name`=excluded.`name`",
+		[]any{"2024-01-01", "12", "test12"}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+
+	underTest := crud.NewCRUD[taggedObj, string](table.Info{Name: "test_table", IDColumn: "id"},
+		executor, "sqlite3")
+
+	_, err := underTest.Upsert(ctx, &taggedObj{Id: "12", Name: "test12", CreatedAt: "2024-01-01", Version: 3})
+	assert.NoError(t, err)
+	executor.AssertExpectations(t)
+}
+
+func TestCRUD_Upsert_Tenant(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.(*crud.CRUD[testObj, string]).SetTable(table.Info{
+		Name:     "test_table",
+		IDColumn: "id",
+		Tenant: table.Tenant{
+			Column:      "name",
+			FromContext: func(context.Context) (any, bool) { return "acme", true },
+		},
+	})
+	c.Executor.On("Exec", ctx,
+		"INSERT INTO `test_table` (`id`, `name`) VALUES (?, ?) ON CONFLICT DO NOTHING ", []any{"12", "acme"}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+
+	_, err := c.UnderTest.Upsert(ctx, &testObj{Id: "12"})
+
+	assert.NoError(t, err)
+}
+
+func TestCRUD_Create_TenantConversion(t *testing.T) {
+	type tenantObj struct {
+		Id     string `db:"id"`
+		Tenant int64  `db:"tenant"`
+	}
+	type stringTenantObj struct {
+		Id     string `db:"id"`
+		Tenant string `db:"tenant"`
+	}
+	tenantInfo := func(value any) table.Info {
+		return table.Info{
+			Name:     "test_table",
+			IDColumn: "id",
+			Tenant: table.Tenant{
+				Column:      "tenant",
+				FromContext: func(context.Context) (any, bool) { return value, true },
+			},
+		}
+	}
+
+	ctx := context.Background()
+	executor := mocks.NewSQLExecutor()
+	executor.On("Exec", ctx,
+		"INSERT INTO `test_table` (`id`, `tenant`) VALUES (?, ?)", []any{"12", int64(7)}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+
+	_, err := crud.NewCRUD[tenantObj, string](tenantInfo(7), executor, "sqlite3").
+		Create(ctx, &tenantObj{Id: "12"})
+	assert.NoError(t, err)
+
+	_, err = crud.NewCRUD[tenantObj, string](tenantInfo([]int64{7}), executor, "sqlite3").
+		Create(ctx, &tenantObj{Id: "12"})
+	assert.ErrorContains(t, err, "[]int64 is not convertible to int64")
+
+	_, err = crud.NewCRUD[stringTenantObj, string](tenantInfo([]int{7}), executor, "sqlite3").
+		Create(ctx, &stringTenantObj{Id: "12"})
+	assert.ErrorContains(t, err, "[]int is not convertible to string")
+
+	_, err = crud.NewCRUD[stringTenantObj, string](tenantInfo(7), executor, "sqlite3").
+		Create(ctx, &stringTenantObj{Id: "12"})
+	assert.ErrorContains(t, err, "int is not convertible to string")
+
+	executor.AssertExpectations(t)
+}
+
+func TestCRUD_Delete_Tenant(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.(*crud.CRUD[testObj, string]).SetTable(table.Info{
+		Name:     "test_table",
+		IDColumn: "id",
+		Tenant: table.Tenant{
+			Column:      "name",
+			FromContext: func(context.Context) (any, bool) { return "acme", true },
+		},
+	})
+	c.Executor.On("Exec", ctx,
+		"DELETE FROM `test_table` WHERE ((`name` = ?) AND (`id` = ?))", []any{"acme", "test_id"}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+
+	_, err := c.UnderTest.Delete(ctx, "test_id")
+
+	assert.NoError(t, err)
+}
+
+func TestCRUD_Schema(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.(*crud.CRUD[testObj, string]).SetTable(table.Info{
+		Name:     "test_table",
+		IDColumn: "id",
+		Schema:   "tenant_42",
+	})
+	c.Executor.On("Exec", ctx,
+		"INSERT INTO `tenant_42`.`test_table` (`id`, `name`) VALUES (?, ?)", []any{"12", "test12"}).
+		Return(mocks.NewSQLResult(1, 1), nil)
+	c.Executor.On("Exec", ctx, "DELETE FROM `tenant_42`.`test_table`", []any(nil)).
+		Return(mocks.NewSQLResult(1, 1), nil)
+
+	_, err := c.UnderTest.Create(ctx, &testObj{Id: "12", Name: "test12"})
+	assert.NoError(t, err)
+	_, err = c.UnderTest.DeleteAll(ctx)
+	assert.NoError(t, err)
+}
+
 type testCaseData struct {
 	ctx       context.Context
 	ctxCancel func()
@@ -196,3 +503,8 @@ type testObj struct {
 	Id   string `db:"id"`
 	Name string `db:"name"`
 }
+
+type autoIDObj struct {
+	Id   int64  `db:"id" goqu:"skipinsert"`
+	Name string `db:"name"`
+}
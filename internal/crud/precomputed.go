@@ -0,0 +1,157 @@
+package crud
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/Klojer/sqlcredo/internal/structmap"
+	"github.com/Klojer/sqlcredo/internal/table"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// idMarker stands for the ID argument when a query is built with markers.
+const idMarker = int64(-1)
+
+// boundQuery is the SQL text of a fixed-shape operation built once,
+// together with the source of each of its arguments.
+type boundQuery struct {
+	sql     string
+	sources []argSource
+}
+
+type argSource struct {
+	field []int // index path of the entity field; nil for the ID argument
+}
+
+// args returns the arguments of the query for entity e (may be invalid if the
+// query has no field arguments) and the given ID.
+func (q *boundQuery) args(e reflect.Value, id any) []any {
+	args := make([]any, len(q.sources))
+	for i, src := range q.sources {
+		if src.field == nil {
+			args[i] = id
+			continue
+		}
+		if f, err := e.FieldByIndexErr(src.field); err == nil {
+			args[i] = f.Interface()
+		}
+	}
+	return args
+}
+
+// precomputedQueries holds the SQL of the fixed-shape operations on an entity type.
+// A nil query means the operation can't be precomputed and is built per call.
+type precomputedQueries struct {
+	selectByID *boundQuery
+	insert     *boundQuery
+	update     *boundQuery
+	deleteByID *boundQuery
+}
+
+// newPrecomputedQueries builds the queries with goqu using unique marker values
+// as arguments, so the rendered SQL gets the dialect's placeholders and the
+// returned arguments reveal which field is bound at each position.
+func newPrecomputedQueries(dialect goqu.DialectWrapper, table table.Info,
+	entityType reflect.Type,
+) precomputedQueries {
+	var res precomputedQueries
+
+	if entityType.Kind() != reflect.Struct || !table.Static() {
+		return res
+	}
+	fields := structmap.Fields(entityType)
+	if len(fields) == 0 {
+		return res
+	}
+	ctx := context.Background()
+
+	columns := make([]any, 0, len(fields))
+	for _, f := range fields {
+		columns = append(columns, goqu.I(f.Column))
+	}
+	res.selectByID = bind(fields, dialect.From(table.From(ctx)).
+		Select(columns...).
+		Where(goqu.I(table.IDColumn).Eq(idMarker)).
+		Prepared(true))
+	res.deleteByID = bind(fields, dialect.Delete(table.Table(ctx)).
+		Where(goqu.I(table.IDColumn).Eq(idMarker)).
+		Prepared(true))
+
+	if hasGoquTag(fields, "defaultifempty") {
+		// the insert/update column set depends on the field values
+		return res
+	}
+
+	insertCols := make([]any, 0, len(fields))
+	insertVals := make(goqu.Vals, 0, len(fields))
+	updateRecord := goqu.Record{}
+	for i, f := range fields {
+		if !hasGoquOption(f, "skipinsert") {
+			insertCols = append(insertCols, f.Column)
+			insertVals = append(insertVals, int64(i))
+		}
+		if !hasGoquOption(f, "skipupdate") {
+			updateRecord[f.Column] = int64(i)
+		}
+	}
+
+	res.insert = bind(fields, dialect.Insert(table.Table(ctx)).
+		Cols(insertCols...).
+		Vals(insertVals).
+		Prepared(true))
+	res.update = bind(fields, dialect.Update(table.Table(ctx)).
+		Set(updateRecord).
+		Where(goqu.I(table.IDColumn).Eq(idMarker)).
+		Prepared(true))
+
+	return res
+}
+
+// sqlBuilder is implemented by the goqu datasets.
+type sqlBuilder interface {
+	ToSQL() (string, []any, error)
+}
+
+func bind(fields []structmap.Field, builder sqlBuilder) *boundQuery {
+	query, markers, err := builder.ToSQL()
+	if err != nil {
+		return nil
+	}
+
+	sources := make([]argSource, 0, len(markers))
+	for _, m := range markers {
+		idx, ok := m.(int64)
+		switch {
+		case !ok:
+			return nil
+		case idx == idMarker:
+			sources = append(sources, argSource{})
+		case idx >= 0 && int(idx) < len(fields):
+			sources = append(sources, argSource{field: fields[idx].Index})
+		default:
+			return nil
+		}
+	}
+
+	return &boundQuery{sql: query, sources: sources}
+}
+
+func hasGoquTag(fields []structmap.Field, option string) bool {
+	for _, f := range fields {
+		if hasGoquOption(f, option) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasGoquOption(f structmap.Field, option string) bool {
+	for _, o := range strings.Split(f.Tag.Get("goqu"), ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Klojer/sqlcredo/internal/goquext"
+	"github.com/Klojer/sqlcredo/internal/predicate"
+	"github.com/Klojer/sqlcredo/internal/table"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+type Reader[T any, I comparable] struct {
+	table      table.Info
+	executor   api.SQLExecutor
+	dialect    goqu.DialectWrapper
+	entityType reflect.Type
+	queries    precomputedQueries
+	maxRows    uint
+}
+
+var _ api.Reader[any, string] = &Reader[any, string]{}
+
+func NewReader[T any, I comparable](table table.Info,
+	executor api.SQLExecutor, driver string,
+) *Reader[T, I] {
+	dialect := goqu.Dialect(goquext.CreateDialectString(driver))
+	entityType := reflect.TypeOf((*T)(nil)).Elem()
+	return &Reader[T, I]{
+		table:      table,
+		executor:   executor,
+		dialect:    dialect,
+		entityType: entityType,
+		queries:    newPrecomputedQueries(dialect, table, entityType),
+	}
+}
+
+// SetMaxRows limits the number of rows GetAll and GetAllInto may load;
+// exceeding the limit fails with api.ErrTooManyRows. Zero means no limit.
+func (r *Reader[T, I]) SetMaxRows(maxRows uint) {
+	r.maxRows = maxRows
+}
+
+func (r *Reader[T, I]) GetAll(ctx context.Context, opts ...api.QueryOpt) ([]T, error) {
+	var records []T
+	if err := r.GetAllInto(ctx, &records, opts...); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *Reader[T, I]) GetAllInto(ctx context.Context, dest any, opts ...api.QueryOpt) error {
+	elemType := goquext.SliceElem(dest)
+	if elemType == nil {
+		return fmt.Errorf("destination must be a pointer to a slice, got %T", dest)
+	}
+
+	params := newQueryParams(opts...)
+
+	where, err := r.where(ctx, params.Filter)
+	if err != nil {
+		return err
+	}
+
+	builder := r.dialect.From(r.table.From(ctx)).
+		Select(goquext.SelectColumns(elemType, params.Columns)...).
+		Prepared(true)
+	if where != nil {
+		builder = builder.Where(where)
+	}
+	if r.maxRows > 0 {
+		// one extra row tells whether the limit is exceeded
+		builder = builder.Limit(r.maxRows + 1)
+	}
+
+	query, args, err := builder.ToSQL()
+	if err != nil {
+		return fmt.Errorf("unable to create 'select all' query: %w", err)
+	}
+	// rows are appended to dest, which may already have elements
+	records := reflect.ValueOf(dest).Elem()
+	before := records.Len()
+	if err := r.selectMany(ctx, dest, query, args...); err != nil {
+		return err
+	}
+
+	if r.maxRows > 0 && records.Len()-before > int(r.maxRows) {
+		return fmt.Errorf("%w: more than %d records", api.ErrTooManyRows, r.maxRows)
+	}
+	return nil
+}
+
+func (r *Reader[T, I]) GetByID(ctx context.Context, id I, opts ...api.QueryOpt) (T, error) {
+	var record T
+	if q := r.queries.selectByID; q != nil && len(opts) == 0 && r.usePrecomputed() {
+		if err := r.executor.SelectOne(ctx, &record, q.sql, q.args(reflect.Value{}, id)...); err != nil {
+			return record, fmt.Errorf("unable to select record: %w", err)
+		}
+		return record, nil
+	}
+
+	params := newQueryParams(opts...)
+
+	where, err := r.where(ctx, api.And(api.Eq(r.table.IDColumn, id), params.Filter))
+	if err != nil {
+		return record, err
+	}
+
+	query, args, err := r.dialect.From(r.table.From(ctx)).
+		Select(goquext.SelectColumns(r.entityType, params.Columns)...).
+		Where(where).
+		Prepared(true).
+		ToSQL()
+	if err != nil {
+		return record, fmt.Errorf("unable to create 'select by id' query: %w", err)
+	}
+
+	err = r.executor.SelectOne(ctx, &record, query, args...)
+	if err != nil {
+		return record, fmt.Errorf("unable to select record: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *Reader[T, I]) GetByIDs(ctx context.Context, ids []I, opts ...api.QueryOpt) ([]T, error) {
+	params := newQueryParams(opts...)
+
+	where, err := r.where(ctx, params.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.dialect.From(r.table.From(ctx)).
+		Select(goquext.SelectColumns(r.entityType, params.Columns)...).
+		Where(goqu.I(r.table.IDColumn).In(ids)).
+		Order(goqu.I(r.table.IDColumn).Asc()).
+		Prepared(true)
+	if where != nil {
+		builder = builder.Where(where)
+	}
+
+	query, args, err := builder.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create 'select by ids' query: %w", err)
+	}
+
+	var entities []T
+	if err := r.selectMany(ctx, &entities, query, args...); err != nil {
+		return nil, fmt.Errorf("unable to select records: %w", err)
+	}
+
+	return entities, nil
+}
+
+func (r *Reader[T, I]) ExistsByID(ctx context.Context, id I) (bool, error) {
+	return r.Exists(ctx, api.Eq(r.table.IDColumn, id))
+}
+
+func (r *Reader[T, I]) Exists(ctx context.Context, filter api.Filter) (bool, error) {
+	where, err := r.where(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+
+	subquery := r.dialect.From(r.table.From(ctx)).Select(goqu.L("1")).Limit(1)
+	if where != nil {
+		subquery = subquery.Where(where)
+	}
+
+	query, args, err := r.dialect.Select(goqu.L("EXISTS ?", subquery)).
+		Prepared(true).
+		ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("unable to create 'exists' query: %w", err)
+	}
+
+	var res bool
+	if err := r.executor.SelectOne(ctx, &res, query, args...); err != nil {
+		return false, fmt.Errorf("unable to check existence: %w", err)
+	}
+	return res, nil
+}
+
+// SetTable replaces the table configuration, e.g. to set its schema or tenant scoping.
+func (r *Reader[T, I]) SetTable(table table.Info) {
+	r.table = table
+	r.queries = newPrecomputedQueries(r.dialect, table, r.entityType)
+}
+
+// usePrecomputed reports whether the precomputed queries apply to all contexts.
+func (r *Reader[T, I]) usePrecomputed() bool {
+	return r.table.Static() && !r.table.Tenant.Enabled()
+}
+
+// where compiles filter scoped to the tenant of ctx.
+func (r *Reader[T, I]) where(ctx context.Context, filter api.Filter) (exp.Expression, error) {
+	filter, err := r.table.Tenant.Scope(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, err := predicate.Compile(filter)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile filter: %w", err)
+	}
+	return where, nil
+}
+
+func (r *Reader[T, I]) selectMany(ctx context.Context, dest any, query string, args ...any) error {
+	if err := r.executor.SelectMany(ctx, dest, query, args...); err != nil {
+		return fmt.Errorf("unable to load records: %w", err)
+	}
+	return nil
+}
+
+func newQueryParams(opts ...api.QueryOpt) api.QueryParams {
+	var params api.QueryParams
+	for _, o := range opts {
+		o(&params)
+	}
+	return params
+}
@@ -0,0 +1,261 @@
+package ddl
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Klojer/sqlcredo/internal/goquext"
+	"github.com/Klojer/sqlcredo/internal/structmap"
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// tagName is the struct tag with column hints, e.g. `sqlcredo:"type:VARCHAR(64),unique,index"`.
+const tagName = "sqlcredo"
+
+// Column describes the DDL of a column.
+type Column struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Unique   bool
+	Default  string // SQL expression, empty for no default
+	Index    bool
+}
+
+// Columns returns the column definitions of the fields of t for the dialect.
+func Columns(t reflect.Type, dialect string) ([]Column, error) {
+	types, ok := typeNames[goquext.CreateDialectString(dialect)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", api.ErrUnsupportedDialect, dialect)
+	}
+
+	fields := structmap.Fields(t)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: %s has no columns", api.ErrUnsupportedType, t)
+	}
+
+	columns := make([]Column, 0, len(fields))
+	for _, f := range fields {
+		c := Column{Name: f.Column}
+		if err := applyHints(&c, f.Tag.Get(tagName)); err != nil {
+			return nil, fmt.Errorf("invalid %s tag of field %s: %w", tagName, f.Name, err)
+		}
+
+		kind, nullable := classify(f.Type)
+		c.Nullable = nullable
+		if c.Type == "" {
+			c.Type = types[kind]
+		}
+		if c.Type == "" {
+			return nil, fmt.Errorf("%w: field %s of type %s", api.ErrUnsupportedType, f.Name, f.Type)
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+// Generate returns the CREATE TABLE statement for entity type t followed by
+// CREATE INDEX statements for columns with the index hint.
+func Generate(t reflect.Type, dialect, table, idColumn string) (string, error) {
+	columns, err := Columns(t, dialect)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	var indexes []string
+	hasID := false
+
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (\n", quote(table))
+	for i, c := range columns {
+		fmt.Fprintf(&b, "    %s %s", quote(c.Name), c.Type)
+		if c.Nullable {
+			b.WriteString(" NULL")
+		} else {
+			b.WriteString(" NOT NULL")
+		}
+		if c.Default != "" {
+			b.WriteString(" DEFAULT " + c.Default)
+		}
+		if c.Name == idColumn {
+			b.WriteString(" PRIMARY KEY")
+			hasID = true
+		} else if c.Unique {
+			b.WriteString(" UNIQUE")
+		}
+		if i < len(columns)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+
+		if c.Index {
+			indexes = append(indexes, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s);\n",
+				quote(table+"_"+c.Name+"_idx"), quote(table), quote(c.Name)))
+		}
+	}
+	b.WriteString(");\n")
+
+	if !hasID {
+		return "", fmt.Errorf("unable to generate schema: no field for id column %q in %s", idColumn, t)
+	}
+
+	for _, idx := range indexes {
+		b.WriteString(idx)
+	}
+	return b.String(), nil
+}
+
+// applyHints sets the column attributes from the comma-separated tag hints.
+func applyHints(c *Column, tag string) error {
+	for _, hint := range splitHints(tag) {
+		key, value, _ := strings.Cut(strings.TrimSpace(hint), ":")
+		switch key {
+		case "type":
+			c.Type = value
+		case "default":
+			c.Default = value
+		case "unique":
+			c.Unique = true
+		case "index":
+			c.Index = true
+		default:
+			return fmt.Errorf("unknown hint %q", key)
+		}
+	}
+	return nil
+}
+
+// splitHints splits tag at commas outside of parentheses and quotes,
+// so hints like type:NUMERIC(10,2) and default:'a,b' stay whole.
+func splitHints(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+
+	var hints []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range tag {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			hints = append(hints, tag[start:i])
+			start = i + 1
+		}
+	}
+	return append(hints, tag[start:])
+}
+
+// kind is a dialect-independent column type.
+type kind int
+
+const (
+	kindUnknown kind = iota
+	kindText
+	kindBool
+	kindSmallInt
+	kindInt
+	kindBigInt
+	kindReal
+	kindDouble
+	kindTime
+	kindBytes
+)
+
+var typeNames = map[string]map[kind]string{
+	"sqlite3": {
+		kindText:     "TEXT",
+		kindBool:     "BOOLEAN",
+		kindSmallInt: "INTEGER",
+		kindInt:      "INTEGER",
+		kindBigInt:   "INTEGER",
+		kindReal:     "REAL",
+		kindDouble:   "REAL",
+		kindTime:     "TIMESTAMP",
+		kindBytes:    "BLOB",
+	},
+	"postgres": {
+		kindText:     "TEXT",
+		kindBool:     "BOOLEAN",
+		kindSmallInt: "SMALLINT",
+		kindInt:      "INTEGER",
+		kindBigInt:   "BIGINT",
+		kindReal:     "REAL",
+		kindDouble:   "DOUBLE PRECISION",
+		kindTime:     "TIMESTAMP",
+		kindBytes:    "BYTEA",
+	},
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// nullTypes maps the sql.Null* types to the kind of their value.
+var nullTypes = map[reflect.Type]kind{
+	reflect.TypeOf(sql.NullString{}):  kindText,
+	reflect.TypeOf(sql.NullBool{}):    kindBool,
+	reflect.TypeOf(sql.NullByte{}):    kindSmallInt,
+	reflect.TypeOf(sql.NullInt16{}):   kindSmallInt,
+	reflect.TypeOf(sql.NullInt32{}):   kindInt,
+	reflect.TypeOf(sql.NullInt64{}):   kindBigInt,
+	reflect.TypeOf(sql.NullFloat64{}): kindDouble,
+	reflect.TypeOf(sql.NullTime{}):    kindTime,
+}
+
+// classify returns the column kind of a field type and whether it is nullable:
+// pointers and sql.Null* types are nullable, other types are not.
+func classify(t reflect.Type) (kind, bool) {
+	nullable := false
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	if k, ok := nullTypes[t]; ok {
+		return k, true
+	}
+
+	switch {
+	case t.ConvertibleTo(timeType) && t.Kind() == reflect.Struct:
+		return kindTime, nullable
+	case t == bytesType || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8):
+		// a nil slice is stored as NULL
+		return kindBytes, true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return kindText, nullable
+	case reflect.Bool:
+		return kindBool, nullable
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return kindSmallInt, nullable
+	case reflect.Int32, reflect.Uint16:
+		return kindInt, nullable
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return kindBigInt, nullable
+	case reflect.Float32:
+		return kindReal, nullable
+	case reflect.Float64:
+		return kindDouble, nullable
+	}
+	return kindUnknown, nullable
+}
+
+// quote quotes an identifier with double quotes, which both sqlite and postgres accept.
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,100 @@
+package ddl_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Klojer/sqlcredo/internal/ddl"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Audit struct {
+	CreatedAt time.Time  `db:"created_at" sqlcredo:"default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+type userID string
+
+type testObj struct {
+	ID       userID         `db:"id"`
+	Email    string         `db:"email" sqlcredo:"type:VARCHAR(255),unique,index"`
+	Nickname *string        `db:"nickname"`
+	Age      int32          `db:"age"`
+	Score    sql.NullInt64  `db:"score"`
+	Balance  float64        `db:"balance"`
+	Active   bool           `db:"active"`
+	Avatar   []byte         `db:"avatar"`
+	Note     sql.NullString `db:"note"`
+	Ignored  string         `db:"-"`
+	Audit
+}
+
+func TestGenerate_Postgres(t *testing.T) {
+	schema, err := ddl.Generate(reflect.TypeOf(testObj{}), "pgx", "users", "id")
+
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE TABLE IF NOT EXISTS "users" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "email" VARCHAR(255) NOT NULL UNIQUE,
+    "nickname" TEXT NULL,
+    "age" INTEGER NOT NULL,
+    "score" BIGINT NULL,
+    "balance" DOUBLE PRECISION NOT NULL,
+    "active" BOOLEAN NOT NULL,
+    "avatar" BYTEA NULL,
+    "note" TEXT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" TIMESTAMP NULL
+);
+CREATE INDEX IF NOT EXISTS "users_email_idx" ON "users" ("email");
+`, schema)
+}
+
+func TestGenerate_SQLite(t *testing.T) {
+	schema, err := ddl.Generate(reflect.TypeOf(testObj{}), "sqlite3", "users", "id")
+
+	require.NoError(t, err)
+	assert.Contains(t, schema, `"age" INTEGER NOT NULL,`)
+	assert.Contains(t, schema, `"balance" REAL NOT NULL,`)
+	assert.Contains(t, schema, `"avatar" BLOB NULL,`)
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	_, err := ddl.Generate(reflect.TypeOf(testObj{}), "mysql", "users", "id")
+	assert.ErrorIs(t, err, api.ErrUnsupportedDialect)
+
+	_, err = ddl.Generate(reflect.TypeOf(struct {
+		ID   string         `db:"id"`
+		Tags map[string]int `db:"tags"`
+	}{}), "sqlite3", "users", "id")
+	assert.ErrorIs(t, err, api.ErrUnsupportedType)
+
+	_, err = ddl.Generate(reflect.TypeOf(testObj{}), "sqlite3", "users", "uuid")
+	assert.ErrorContains(t, err, `no field for id column "uuid"`)
+}
+
+func TestColumns_HintsWithCommas(t *testing.T) {
+	columns, err := ddl.Columns(reflect.TypeOf(struct {
+		Price  float64 `db:"price" sqlcredo:"type:NUMERIC(10,2),default:round(1.5, 1)"`
+		Status string  `db:"status" sqlcredo:"default:'new,open',index"`
+	}{}), "postgres")
+
+	require.NoError(t, err)
+	assert.Equal(t, []ddl.Column{
+		{Name: "price", Type: "NUMERIC(10,2)", Default: "round(1.5, 1)"},
+		{Name: "status", Type: "TEXT", Default: "'new,open'", Index: true},
+	}, columns)
+}
+
+func TestColumns_UnknownHint(t *testing.T) {
+	_, err := ddl.Columns(reflect.TypeOf(struct {
+		ID string `db:"id" sqlcredo:"type:TEXT,primary"`
+	}{}), "sqlite3")
+
+	assert.ErrorContains(t, err, `invalid sqlcredo tag of field ID: unknown hint "primary"`)
+}
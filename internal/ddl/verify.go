@@ -0,0 +1,147 @@
+package ddl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Klojer/sqlcredo/internal/goquext"
+	"github.com/Klojer/sqlcredo/internal/structmap"
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// LiveColumn describes a column of a live table.
+type LiveColumn struct {
+	Name       string         `db:"name"`
+	NotNull    bool           `db:"not_null"`
+	Default    sql.NullString `db:"default_value"`
+	PrimaryKey bool           `db:"primary_key"`
+}
+
+const sqliteColumnsQuery = `SELECT name, "notnull" AS not_null, dflt_value AS default_value, pk > 0 AS primary_key
+FROM pragma_table_info(?, ?) ORDER BY cid`
+
+const postgresColumnsQuery = `SELECT c.column_name AS name,
+    c.is_nullable = 'NO' AS not_null,
+    c.column_default AS default_value,
+    EXISTS (
+        SELECT 1 FROM information_schema.table_constraints tc
+        JOIN information_schema.key_column_usage k
+            ON k.constraint_schema = tc.constraint_schema AND k.constraint_name = tc.constraint_name
+        WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema
+            AND tc.table_name = c.table_name AND k.column_name = c.column_name
+    ) AS primary_key
+FROM information_schema.columns c
+WHERE c.table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND c.table_name = $2
+ORDER BY c.ordinal_position`
+
+// Inspect returns the columns of the live table; a missing table has no columns.
+func Inspect(ctx context.Context, executor api.SQLExecutor, dialect, schema, table string,
+) ([]LiveColumn, error) {
+	var query string
+	var args []any
+	switch goquext.CreateDialectString(dialect) {
+	case "sqlite3":
+		if schema == "" {
+			schema = "main"
+		}
+		query, args = sqliteColumnsQuery, []any{table, schema}
+	case "postgres":
+		query, args = postgresColumnsQuery, []any{schema, table}
+	default:
+		return nil, fmt.Errorf("%w: %s", api.ErrUnsupportedDialect, dialect)
+	}
+
+	var columns []LiveColumn
+	if err := executor.SelectMany(ctx, &columns, query, args...); err != nil {
+		return nil, fmt.Errorf("unable to inspect table %q: %w", table, err)
+	}
+	return columns, nil
+}
+
+// Verify compares the fields of t with the columns of the live table and returns
+// an *api.SchemaDriftError listing the mismatches, or nil if there are none.
+func Verify(t reflect.Type, live []LiveColumn, table, idColumn string) error {
+	drift := &api.SchemaDriftError{Table: table}
+	if len(live) == 0 {
+		drift.Issues = append(drift.Issues, api.SchemaIssue{Message: "table does not exist"})
+		return drift
+	}
+
+	byName := make(map[string]LiveColumn, len(live))
+	var primaryKey []string
+	for _, c := range live {
+		if c.PrimaryKey {
+			// sqlite reports primary key columns as nullable, e.g. INTEGER PRIMARY KEY
+			c.NotNull = true
+			primaryKey = append(primaryKey, c.Name)
+		}
+		byName[c.Name] = c
+	}
+
+	fields := structmap.Fields(t)
+	mapped := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		mapped[f.Column] = true
+
+		c, ok := byName[f.Column]
+		if !ok {
+			add(drift, f.Column, fmt.Sprintf("missing in table, mapped by field %s", f.Name))
+			continue
+		}
+
+		nullable, known := fieldNullable(f.Type)
+		switch {
+		case !known:
+		case nullable && c.NotNull:
+			add(drift, f.Column, fmt.Sprintf("NOT NULL, but field %s of type %s may be nil", f.Name, f.Type))
+		case !nullable && !c.NotNull:
+			add(drift, f.Column, fmt.Sprintf("nullable, but field %s of type %s can't hold NULL", f.Name, f.Type))
+		}
+	}
+
+	if !mapped[idColumn] {
+		add(drift, idColumn, "id column is not mapped by any field")
+	}
+	if len(primaryKey) != 1 || primaryKey[0] != idColumn {
+		add(drift, idColumn, fmt.Sprintf("id column is not the primary key (primary key: %s)",
+			strings.Join(primaryKey, ", ")))
+	}
+
+	for _, c := range live {
+		if !mapped[c.Name] && c.NotNull && !c.Default.Valid && !c.PrimaryKey {
+			add(drift, c.Name, "NOT NULL without default, but not mapped by any field, so inserts fail")
+		}
+	}
+
+	if len(drift.Issues) == 0 {
+		return nil
+	}
+	return drift
+}
+
+// fieldNullable reports whether a field type can hold NULL, and whether that is known:
+// byte slices and custom scanners decide by their value.
+func fieldNullable(t reflect.Type) (nullable bool, known bool) {
+	if t.Kind() == reflect.Pointer {
+		return true, true
+	}
+	if _, ok := nullTypes[t]; ok {
+		return true, true
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return false, false
+	}
+	if reflect.PointerTo(t).Implements(scannerType) {
+		return false, false
+	}
+	return false, true
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+func add(drift *api.SchemaDriftError, column, message string) {
+	drift.Issues = append(drift.Issues, api.SchemaIssue{Column: column, Message: message})
+}
@@ -0,0 +1,37 @@
+package goquext
+
+import (
+	"reflect"
+
+	"github.com/Klojer/sqlcredo/internal/structmap"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// SelectColumns returns the select list for a query loading rows of type t.
+// Explicit columns take precedence; otherwise the columns mapped by t are used.
+// Falls back to "*" if t is not a struct.
+func SelectColumns(t reflect.Type, columns []string) []any {
+	if len(columns) == 0 {
+		columns = structmap.Columns(t)
+	}
+	if len(columns) == 0 {
+		return []any{goqu.Star()}
+	}
+
+	res := make([]any, 0, len(columns))
+	for _, c := range columns {
+		res = append(res, goqu.I(c))
+	}
+	return res
+}
+
+// SliceElem returns the element type of dest, which must be a pointer to a slice.
+// Returns nil otherwise.
+func SliceElem(dest any) reflect.Type {
+	t := reflect.TypeOf(dest)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Slice {
+		return nil
+	}
+	return t.Elem().Elem()
+}
@@ -1,13 +1,37 @@
 package goquext
 
 import (
+	"github.com/doug-martin/goqu/v9"
 	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
-	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
+	"github.com/doug-martin/goqu/v9/dialect/sqlite3"
 )
 
+// sqliteUpsertDialect is the sqlite3 dialect without INSERT OR IGNORE,
+// with the postgres conflict spacing and conditional conflict updates,
+// which sqlite supports since 3.24.
+const sqliteUpsertDialect = "sqlite3_upsert"
+
+func init() {
+	opts := sqlite3.DialectOptions()
+	opts.SupportsInsertIgnoreSyntax = false
+	opts.ConflictFragment = []byte(" ON CONFLICT")
+	opts.SupportsConflictUpdateWhere = true
+	goqu.RegisterDialect(sqliteUpsertDialect, opts)
+}
+
 func CreateDialectString(driver string) string {
 	if driver == "pgx" {
 		return "postgres"
 	}
 	return driver
 }
+
+// CreateUpsertDialectString returns the dialect for inserts with ON CONFLICT clauses.
+// goqu's sqlite3 dialect turns them into INSERT OR IGNORE, which also
+// ignores NOT NULL and CHECK violations.
+func CreateUpsertDialectString(driver string) string {
+	if driver == "sqlite3" {
+		return sqliteUpsertDialect
+	}
+	return CreateDialectString(driver)
+}
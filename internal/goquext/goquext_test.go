@@ -1,10 +1,12 @@
 package goquext_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/Klojer/sqlcredo/internal/goquext"
 
+	"github.com/doug-martin/goqu/v9"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,3 +30,50 @@ func TestCreateDialectString(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectColumns(t *testing.T) {
+	type obj struct {
+		ID   string `db:"id"`
+		Name string `db:"name"`
+	}
+	dialect := goqu.Dialect("sqlite3")
+
+	tests := []struct {
+		name    string
+		t       reflect.Type
+		columns []string
+		want    string
+	}{
+		{name: "Struct columns", t: reflect.TypeOf(obj{}), want: "SELECT `id`, `name` FROM `t`"},
+		{name: "Explicit columns", t: reflect.TypeOf(obj{}), columns: []string{"name"}, want: "SELECT `name` FROM `t`"},
+		{name: "Non-struct type", t: reflect.TypeOf(""), want: "SELECT * FROM `t`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := dialect.From("t").Select(goquext.SelectColumns(tt.t, tt.columns)...).ToSQL()
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSliceElem(t *testing.T) {
+	var names []string
+
+	assert.Equal(t, reflect.TypeOf(""), goquext.SliceElem(&names))
+	assert.Nil(t, goquext.SliceElem(names))
+	assert.Nil(t, goquext.SliceElem(nil))
+}
+
+func TestCreateUpsertDialectString(t *testing.T) {
+	conflict := goqu.DoUpdate("id", goqu.Record{"name": goqu.L("excluded.name")})
+
+	sqlite, _, err := goqu.Dialect(goquext.CreateUpsertDialectString("sqlite3")).
+		Insert("t").Rows(goqu.Record{"id": 1}).OnConflict(conflict).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `t` (`id`) VALUES (1) ON CONFLICT (id) DO UPDATE SET `name`=excluded.name", sqlite)
+
+	assert.Equal(t, "postgres", goquext.CreateUpsertDialectString("pgx"))
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"reflect"
+	"strings"
 
 	"github.com/Klojer/sqlcredo/internal/goquext"
+	"github.com/Klojer/sqlcredo/internal/predicate"
 	"github.com/Klojer/sqlcredo/internal/table"
 	"github.com/Klojer/sqlcredo/pkg/api"
 
@@ -13,14 +16,12 @@ import (
 	"github.com/doug-martin/goqu/v9/exp"
 )
 
-const (
-	countQueryTemplate = `SELECT COUNT(%s) FROM %s;`
-)
-
 type PageResolver[T any] struct {
 	table      table.Info
 	executor   api.SQLExecutor
 	countQuery string
+	countArgs  []any
+	countErr   error
 	emptyPage  api.Page[T]
 	dialect    goqu.DialectWrapper
 }
@@ -30,59 +31,145 @@ var _ api.PageResolver[any] = &PageResolver[any]{}
 func NewPageResolver[T any](table table.Info,
 	executor api.SQLExecutor, driver string,
 ) *PageResolver[T] {
-	return &PageResolver[T]{
-		table:      table,
-		executor:   executor,
-		countQuery: fmt.Sprintf(countQueryTemplate, table.IDColumn, table.Name),
-		emptyPage:  newEmptyPage[T](),
-		dialect:    goqu.Dialect(goquext.CreateDialectString(driver)),
+	r := &PageResolver[T]{
+		executor:  executor,
+		emptyPage: newEmptyPage[T](),
+		dialect:   goqu.Dialect(goquext.CreateDialectString(driver)),
+	}
+	r.SetTable(table)
+	return r
+}
+
+// SetTable replaces the table configuration, e.g. to set its schema or tenant scoping.
+func (r *PageResolver[T]) SetTable(table table.Info) {
+	r.table = table
+	if table.Static() {
+		// the count query is built once here; a failure is reported by Count
+		r.countQuery, r.countArgs, r.countErr = r.buildCountQuery(context.Background())
 	}
 }
 
+func (r *PageResolver[T]) buildCountQuery(ctx context.Context) (string, []any, error) {
+	return r.dialect.From(r.table.From(ctx)).
+		Select(goqu.COUNT(goqu.I(r.table.IDColumn))).
+		Prepared(true).
+		ToSQL()
+}
+
 func (r *PageResolver[T]) GetPage(ctx context.Context, opts ...api.PageOpt) (api.Page[T], error) {
+	var pageRecords []T
+	info, err := r.GetPageInto(ctx, &pageRecords, opts...)
+	if err != nil {
+		return r.emptyPage, err
+	}
+
+	if len(pageRecords) == 0 {
+		return r.emptyPage, nil
+	}
+
+	return api.Page[T]{
+		Number:     info.Number,
+		Size:       info.Size,
+		Total:      info.Total,
+		TotalPages: info.TotalPages,
+		Content:    pageRecords,
+	}, nil
+}
+
+func (r *PageResolver[T]) GetPageInto(ctx context.Context, dest any, opts ...api.PageOpt) (api.PageInfo, error) {
 	req, err := newPageParams(r.table.IDColumn, opts...)
 	if err != nil {
-		return r.emptyPage, fmt.Errorf("unable to create page request: %w", err)
+		return api.PageInfo{}, fmt.Errorf("unable to create page request: %w", err)
 	}
 
-	query, args, err := r.createPageQueryBuilder(req)
+	elemType := goquext.SliceElem(dest)
+	if elemType == nil {
+		return api.PageInfo{}, fmt.Errorf("page destination must be a pointer to a slice, got %T", dest)
+	}
+
+	req.Filter, err = r.table.Tenant.Scope(ctx, req.Filter)
 	if err != nil {
-		return r.emptyPage, fmt.Errorf("unable to create page sql query: %w", err)
+		return api.PageInfo{}, err
 	}
 
-	pageRecords, err := r.selectMany(ctx, query, args...)
+	query, args, err := r.createPageQueryBuilder(ctx, req, elemType)
 	if err != nil {
-		return r.emptyPage, fmt.Errorf("unable to get page items: %w", err)
+		return api.PageInfo{}, fmt.Errorf("unable to create page sql query: %w", err)
 	}
 
-	totalRecords, err := r.Count(ctx)
+	if err := r.selectMany(ctx, dest, query, args...); err != nil {
+		return api.PageInfo{}, fmt.Errorf("unable to get page items: %w", err)
+	}
+
+	totalRecords, err := r.countWhere(ctx, req.Filter)
 	if err != nil {
-		return r.emptyPage, fmt.Errorf("unable to count all items: %w", err)
+		return api.PageInfo{}, fmt.Errorf("unable to count all items: %w", err)
 	}
 
-	if len(pageRecords) == 0 {
-		return r.emptyPage, nil
+	pageSize := reflect.ValueOf(dest).Elem().Len()
+	if pageSize == 0 {
+		return api.PageInfo{}, nil
 	}
 
 	totalPages := uint(math.Ceil(float64(totalRecords) / float64(req.PageSize)))
 
-	return api.Page[T]{
+	return api.PageInfo{
 		Number:     req.PageNumber,
-		Size:       uint(len(pageRecords)),
+		Size:       uint(pageSize),
 		Total:      totalRecords,
 		TotalPages: totalPages,
-		Content:    pageRecords,
 	}, nil
 }
 
-func (r *PageResolver[T]) createPageQueryBuilder(params api.PageParams) (string, []any, error) {
-	builder := r.dialect.From(r.table.Name).Prepared(true)
-	builder = builder.Offset(params.PageNumber * params.PageSize)
-	builder = builder.Limit(params.PageSize)
-	builder = builder.Order(buildOrderExprs(params)...)
+func (r *PageResolver[T]) createPageQueryBuilder(ctx context.Context, params api.PageParams,
+	elemType reflect.Type,
+) (string, []any, error) {
+	where, err := predicate.Compile(params.Filter)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to compile filter: %w", err)
+	}
+
+	builder := r.dialect.From(r.table.From(ctx)).Prepared(true)
+	if where != nil {
+		builder = builder.Where(where)
+	}
+	builder = builder.Select(goquext.SelectColumns(elemType, params.Columns)...)
+	builder = applyPaging(builder, params)
 	return builder.ToSQL()
 }
 
+func applyPaging(builder *goqu.SelectDataset, params api.PageParams) *goqu.SelectDataset {
+	if len(params.After) > 0 {
+		builder = builder.Where(buildKeysetExpr(params))
+	} else {
+		builder = builder.Offset(params.PageNumber * params.PageSize)
+	}
+	builder = builder.Limit(params.PageSize)
+	return builder.Order(buildOrderExprs(params)...)
+}
+
+// buildKeysetExpr selects the rows following the cursor in the sort order
+// with a row value comparison, e.g. ("name", "id") > (?, ?).
+func buildKeysetExpr(params api.PageParams) exp.Expression {
+	op := ">"
+	if params.SortDesc {
+		op = "<"
+	}
+
+	if len(params.SortBy) == 1 {
+		return goqu.L("(? "+op+" ?)", goqu.I(params.SortBy[0]), params.After[0])
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(params.SortBy)), ", ")
+	operands := make([]any, 0, 2*len(params.SortBy))
+	for _, s := range params.SortBy {
+		operands = append(operands, goqu.I(s))
+	}
+	operands = append(operands, params.After...)
+
+	return goqu.L("(("+placeholders+") "+op+" ("+placeholders+"))", operands...)
+}
+
 func buildOrderExprs(params api.PageParams) []exp.OrderedExpression {
 	orderExprs := make([]exp.OrderedExpression, 0, len(params.SortBy))
 	for _, s := range params.SortBy {
@@ -96,19 +183,68 @@ func buildOrderExprs(params api.PageParams) []exp.OrderedExpression {
 }
 
 func (r *PageResolver[T]) Count(ctx context.Context) (uint64, error) {
+	if r.table.Tenant.Enabled() {
+		return r.CountWhere(ctx, api.Filter{})
+	}
+
+	query, args, err := r.countQuery, r.countArgs, r.countErr
+	if !r.table.Static() {
+		query, args, err = r.buildCountQuery(ctx)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to create 'count' query: %w", err)
+	}
+
+	var res uint64
+	if err := r.executor.SelectOne(api.WithQueryKind(ctx, api.QueryCount), &res, query, args...); err != nil {
+		return 0, fmt.Errorf("unable to count records: %w", err)
+	}
+	return res, nil
+}
+
+func (r *PageResolver[T]) CountWhere(ctx context.Context, filter api.Filter) (uint64, error) {
+	filter, err := r.table.Tenant.Scope(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return r.countWhere(ctx, filter)
+}
+
+// countWhere counts the records matching filter, which must be already scoped.
+func (r *PageResolver[T]) countWhere(ctx context.Context, filter api.Filter) (uint64, error) {
+	if filter.IsZero() {
+		return r.Count(ctx)
+	}
+
+	where, err := predicate.Compile(filter)
+	if err != nil {
+		return 0, fmt.Errorf("unable to compile filter: %w", err)
+	}
+
+	builder := r.dialect.From(r.table.From(ctx)).
+		Select(goqu.COUNT(goqu.I(r.table.IDColumn))).
+		Prepared(true)
+	if where != nil {
+		builder = builder.Where(where)
+	}
+
+	query, args, err := builder.ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("unable to create 'count' query: %w", err)
+	}
+
 	var res uint64
-	if err := r.executor.SelectOne(ctx, &res, r.countQuery); err != nil {
+	if err := r.executor.SelectOne(api.WithQueryKind(ctx, api.QueryCount), &res, query, args...); err != nil {
 		return 0, fmt.Errorf("unable to count records: %w", err)
 	}
 	return res, nil
 }
 
-func (r *PageResolver[T]) selectMany(ctx context.Context, query string, args ...any) ([]T, error) {
-	var records []T
-	if err := r.executor.SelectMany(ctx, &records, query, args...); err != nil {
-		return nil, fmt.Errorf("unable to load page records: %w", err)
+func (r *PageResolver[T]) selectMany(ctx context.Context, dest any, query string, args ...any) error {
+	if err := r.executor.SelectMany(ctx, dest, query, args...); err != nil {
+		return fmt.Errorf("unable to load page records: %w", err)
 	}
-	return records, nil
+	return nil
 }
 
 func newPageParams(idColumn string, opts ...api.PageOpt) (api.PageParams, error) {
@@ -126,10 +262,15 @@ func newPageParams(idColumn string, opts ...api.PageOpt) (api.PageParams, error)
 		return api.PageParams{}, fmt.Errorf("invalid page params: %w", err)
 	}
 
-	if params.SortBy == nil {
+	if params.SortBy == nil && idColumn != "" {
 		params.SortBy = []string{idColumn}
 	}
 
+	if len(params.After) > 0 && len(params.After) != len(params.SortBy) {
+		return api.PageParams{}, fmt.Errorf("cursor has %d values for %d sort columns: %w",
+			len(params.After), len(params.SortBy), api.ErrInvalidCursor)
+	}
+
 	return params, nil
 }
 
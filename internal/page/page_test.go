@@ -10,6 +10,7 @@ import (
 	"github.com/Klojer/sqlcredo/internal/table"
 	"github.com/Klojer/sqlcredo/pkg/api"
 
+	"github.com/doug-martin/goqu/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -17,10 +18,10 @@ import (
 func TestPageResolver_GetPage(t *testing.T) {
 	c, ctx := newTestCase(t)
 	c.Executor.On("SelectMany", ctx, mock.Anything,
-		"SELECT * FROM `test_table` ORDER BY `id` ASC LIMIT ?", []any{int64(10)}).
+		"SELECT `id`, `name` FROM `test_table` ORDER BY `id` ASC LIMIT ?", []any{int64(10)}).
 		Return(nil)
-	c.Executor.On("SelectOne", ctx, mock.Anything,
-		"SELECT COUNT(id) FROM test_table;", mock.Anything).
+	c.Executor.On("SelectOne", countCtx, mock.Anything,
+		"SELECT COUNT(`id`) FROM `test_table`", mock.Anything).
 		Return(nil)
 
 	_, err := c.UnderTest.GetPage(ctx, api.WithPageNumber(0), api.WithPageSize(10))
@@ -28,10 +29,59 @@ func TestPageResolver_GetPage(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestPageResolver_GetPageInto(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectMany", ctx, mock.Anything,
+		"SELECT `name` FROM `test_table` ORDER BY `name` ASC LIMIT ? OFFSET ?",
+		[]any{int64(5), int64(10)}).
+		Return(nil)
+	c.Executor.On("SelectOne", countCtx, mock.Anything,
+		"SELECT COUNT(`id`) FROM `test_table`", mock.Anything).
+		Return(nil)
+
+	var dest []struct {
+		Name string `db:"name"`
+	}
+	_, err := c.UnderTest.(*page.PageResolver[testObj]).GetPageInto(ctx, &dest,
+		api.WithPageNumber(2), api.WithPageSize(5), api.WithSortBy("name"))
+
+	assert.NoError(t, err)
+}
+
+func TestPageResolver_GetPage_Filter(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectMany", ctx, mock.Anything,
+		"SELECT `id`, `name` FROM `test_table` WHERE (`name` = ?) ORDER BY `id` ASC LIMIT ?",
+		[]any{"John", int64(10)}).
+		Return(nil)
+	c.Executor.On("SelectOne", countCtx, mock.Anything,
+		"SELECT COUNT(`id`) FROM `test_table` WHERE (`name` = ?)", []any{"John"}).
+		Return(nil)
+
+	_, err := c.UnderTest.GetPage(ctx, api.WithFilter(api.Eq("name", "John")))
+
+	assert.NoError(t, err)
+}
+
+func TestPageResolver_GetPage_Keyset(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectMany", ctx, mock.Anything,
+		"SELECT `id`, `name` FROM `test_table` WHERE (`id` < ?) ORDER BY `id` DESC LIMIT ?",
+		[]any{"u3", int64(10)}).
+		Return(nil)
+	c.Executor.On("SelectOne", countCtx, mock.Anything,
+		"SELECT COUNT(`id`) FROM `test_table`", mock.Anything).
+		Return(nil)
+
+	_, err := c.UnderTest.GetPage(ctx, api.WithSortDesc("id"), api.WithAfter("u3"))
+
+	assert.NoError(t, err)
+}
+
 func TestPageResolver_Count(t *testing.T) {
 	c, ctx := newTestCase(t)
-	c.Executor.On("SelectOne", ctx, mock.Anything,
-		"SELECT COUNT(id) FROM test_table;", mock.Anything).
+	c.Executor.On("SelectOne", countCtx, mock.Anything,
+		"SELECT COUNT(`id`) FROM `test_table`", mock.Anything).
 		Return(nil)
 
 	_, err := c.UnderTest.Count(ctx)
@@ -39,6 +89,63 @@ func TestPageResolver_Count(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestPageResolver_CountWhere(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.Executor.On("SelectOne", countCtx, mock.Anything,
+		"SELECT COUNT(`id`) FROM `test_table` WHERE (`name` LIKE ?)", []any{"Jo%"}).
+		Return(nil)
+
+	_, err := c.UnderTest.CountWhere(ctx, api.Like("name", "Jo%"))
+
+	assert.NoError(t, err)
+}
+
+func TestPageResolver_Count_Source(t *testing.T) {
+	executor := mocks.NewSQLExecutor()
+	tableInfo := table.Info{
+		Name:     "src",
+		IDColumn: "id",
+		Source:   goqu.L("(SELECT * FROM test_table WHERE name = ?)", "John").As("src"),
+	}
+	resolver := page.NewPageResolver[testObj](tableInfo, executor, "sqlite3")
+
+	ctx := context.Background()
+	executor.On("SelectOne", countCtx, mock.Anything,
+		"SELECT COUNT(`id`) FROM (SELECT * FROM test_table WHERE name = ?) AS `src`", []any{"John"}).
+		Return(nil)
+
+	_, err := resolver.Count(ctx)
+
+	assert.NoError(t, err)
+	executor.AssertExpectations(t)
+}
+
+func TestPageResolver_Count_SchemaResolver(t *testing.T) {
+	executor := mocks.NewSQLExecutor()
+	tableInfo := table.Info{
+		Name:              "test_table",
+		IDColumn:          "id",
+		Schema:            "public",
+		SchemaFromContext: func(ctx context.Context) string { s, _ := ctx.Value(schemaKey{}).(string); return s },
+	}
+	resolver := page.NewPageResolver[testObj](tableInfo, executor, "postgres")
+
+	executor.On("SelectOne", countCtx, mock.Anything,
+		`SELECT COUNT("id") FROM "tenant_42"."test_table"`, []any{}).
+		Return(nil)
+	executor.On("SelectOne", countCtx, mock.Anything,
+		`SELECT COUNT("id") FROM "public"."test_table"`, []any{}).
+		Return(nil)
+
+	_, err := resolver.Count(context.WithValue(context.Background(), schemaKey{}, "tenant_42"))
+	assert.NoError(t, err)
+	_, err = resolver.Count(context.Background())
+	assert.NoError(t, err)
+	executor.AssertExpectations(t)
+}
+
+type schemaKey struct{}
+
 type testCaseData struct {
 	ctx       context.Context
 	ctxCancel func()
@@ -81,3 +188,9 @@ type testObj struct {
 	Id   string `db:"id"`
 	Name string `db:"name"`
 }
+
+// countCtx matches the context of counting queries.
+var countCtx = mock.MatchedBy(func(ctx context.Context) bool {
+	kind, ok := api.QueryKindFrom(ctx)
+	return ok && kind == api.QueryCount
+})
@@ -0,0 +1,121 @@
+package page
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Klojer/sqlcredo/internal/goquext"
+	"github.com/Klojer/sqlcredo/internal/predicate"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+const queryAlias = "q"
+
+// sourceMarker stands for the wrapped query in generated SQL. The query is inserted
+// after generation, so its placeholders aren't confused with the generated ones.
+const sourceMarker = "sqlcredo_source_query"
+
+var dollarPlaceholder = regexp.MustCompile(`\$(\d+)`)
+
+// QueryInto loads a single page of the results of an arbitrary select query into dest,
+// which must be a pointer to a slice. The query is wrapped in a subquery, so sorting,
+// filtering and paging are applied to its result columns. Query arguments keep the
+// placeholder style of the driver; the arguments of the wrapping follow them.
+func QueryInto(ctx context.Context, executor api.SQLExecutor, driver string,
+	dest any, query string, args []any, opts ...api.PageOpt,
+) (api.PageInfo, error) {
+	if goquext.SliceElem(dest) == nil {
+		return api.PageInfo{}, fmt.Errorf("page destination must be a pointer to a slice, got %T", dest)
+	}
+
+	req, err := newPageParams("", opts...)
+	if err != nil {
+		return api.PageInfo{}, fmt.Errorf("unable to create page request: %w", err)
+	}
+
+	where, err := predicate.Compile(req.Filter)
+	if err != nil {
+		return api.PageInfo{}, fmt.Errorf("unable to compile filter: %w", err)
+	}
+
+	dialectName := goquext.CreateDialectString(driver)
+	dialect := goqu.Dialect(dialectName)
+	source := goqu.L("(" + sourceMarker + ")").As(queryAlias)
+
+	pageBuilder := dialect.From(source).Prepared(true)
+	countBuilder := dialect.From(source).Select(goqu.COUNT(goqu.Star())).Prepared(true)
+	if where != nil {
+		pageBuilder = pageBuilder.Where(where)
+		countBuilder = countBuilder.Where(where)
+	}
+	if len(req.Columns) > 0 {
+		pageBuilder = pageBuilder.Select(goquext.SelectColumns(nil, req.Columns)...)
+	}
+
+	pageQuery, pageArgs, err := wrapQuery(applyPaging(pageBuilder, req), dialectName, query, args)
+	if err != nil {
+		return api.PageInfo{}, fmt.Errorf("unable to create page sql query: %w", err)
+	}
+
+	countQuery, countArgs, err := wrapQuery(countBuilder, dialectName, query, args)
+	if err != nil {
+		return api.PageInfo{}, fmt.Errorf("unable to create 'count' query: %w", err)
+	}
+
+	if err := executor.SelectMany(ctx, dest, pageQuery, pageArgs...); err != nil {
+		return api.PageInfo{}, fmt.Errorf("unable to get page items: %w", err)
+	}
+
+	var totalRecords uint64
+	if err := executor.SelectOne(api.WithQueryKind(ctx, api.QueryCount), &totalRecords,
+		countQuery, countArgs...); err != nil {
+		return api.PageInfo{}, fmt.Errorf("unable to count all items: %w", err)
+	}
+
+	pageSize := reflect.ValueOf(dest).Elem().Len()
+	if pageSize == 0 {
+		return api.PageInfo{}, nil
+	}
+
+	return api.PageInfo{
+		Number:     req.PageNumber,
+		Size:       uint(pageSize),
+		Total:      totalRecords,
+		TotalPages: uint(math.Ceil(float64(totalRecords) / float64(req.PageSize))),
+	}, nil
+}
+
+// wrapQuery generates the SQL of builder and inserts query as its source. The source
+// comes first in the generated SQL, so its arguments precede the generated ones,
+// and numbered placeholders of the generated SQL are shifted past them.
+func wrapQuery(builder *goqu.SelectDataset, dialect, query string, args []any) (string, []any, error) {
+	sql, builderArgs, err := builder.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if dialect == "postgres" && len(args) > 0 {
+		sql = dollarPlaceholder.ReplaceAllStringFunc(sql, func(p string) string {
+			n, _ := strconv.Atoi(p[1:])
+			return "$" + strconv.Itoa(n+len(args))
+		})
+	}
+	sql = strings.Replace(sql, sourceMarker, trimQuery(query), 1)
+
+	if len(builderArgs) == 0 {
+		return sql, args, nil
+	}
+	return sql, append(append(make([]any, 0, len(args)+len(builderArgs)), args...), builderArgs...), nil
+}
+
+// trimQuery removes trailing semicolons, which are not allowed in subqueries.
+func trimQuery(query string) string {
+	return strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+}
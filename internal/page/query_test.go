@@ -0,0 +1,69 @@
+package page_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/internal/mocks"
+	"github.com/Klojer/sqlcredo/internal/page"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQueryInto(t *testing.T) {
+	executor := mocks.NewSQLExecutor()
+	ctx := context.Background()
+	query := "SELECT * FROM users WHERE age > $1;"
+
+	executor.On("SelectMany", ctx, mock.Anything,
+		`SELECT * FROM (SELECT * FROM users WHERE age > $1) AS "q" `+
+			`WHERE ("name" LIKE $2) ORDER BY "name" DESC LIMIT $3 OFFSET $4`,
+		[]any{18, "J%", int64(5), int64(10)}).
+		Return(nil)
+	executor.On("SelectOne", countCtx, mock.Anything,
+		`SELECT COUNT(*) FROM (SELECT * FROM users WHERE age > $1) AS "q" WHERE ("name" LIKE $2)`,
+		[]any{18, "J%"}).
+		Return(nil)
+
+	var dest []testObj
+	_, err := page.QueryInto(ctx, executor, "pgx", &dest, query, []any{18},
+		api.WithPageNumber(2), api.WithPageSize(5), api.WithSortBy("name"), api.WithSortDesc("name"),
+		api.WithFilter(api.Like("name", "J%")))
+
+	assert.NoError(t, err)
+	executor.AssertExpectations(t)
+}
+
+func TestQueryInto_Keyset(t *testing.T) {
+	executor := mocks.NewSQLExecutor()
+	ctx := context.Background()
+
+	executor.On("SelectMany", ctx, mock.Anything,
+		"SELECT `id` FROM (SELECT id, name FROM users WHERE active = ?) AS `q` "+
+			"WHERE ((`name`, `id`) > (?, ?)) ORDER BY `name` ASC, `id` ASC LIMIT ?",
+		[]any{true, "Ann", "u3", int64(2)}).
+		Return(nil)
+	executor.On("SelectOne", countCtx, mock.Anything,
+		"SELECT COUNT(*) FROM (SELECT id, name FROM users WHERE active = ?) AS `q`", []any{true}).
+		Return(nil)
+
+	var dest []string
+	_, err := page.QueryInto(ctx, executor, "sqlite3", &dest, "SELECT id, name FROM users WHERE active = ?", []any{true},
+		api.WithPageSize(2), api.WithSortBy("name"), api.WithSortBy("id"),
+		api.WithAfter("Ann", "u3"), api.WithColumns("id"))
+
+	assert.NoError(t, err)
+	executor.AssertExpectations(t)
+}
+
+func TestQueryInto_InvalidCursor(t *testing.T) {
+	executor := mocks.NewSQLExecutor()
+
+	var dest []testObj
+	_, err := page.QueryInto(context.Background(), executor, "sqlite3", &dest, "SELECT 1", nil,
+		api.WithSortBy("name"), api.WithAfter("Ann", "u3"))
+
+	assert.ErrorIs(t, err, api.ErrInvalidCursor)
+}
@@ -0,0 +1,131 @@
+package predicate
+
+import (
+	"fmt"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+var (
+	matchNone = goqu.L("1 = 0")
+	matchAll  = goqu.L("1 = 1")
+)
+
+// Compile converts a filter into a goqu expression suitable for Where clauses.
+// Returns nil for the zero filter, which matches all records.
+func Compile(f api.Filter) (exp.Expression, error) {
+	if f.IsZero() {
+		return nil, nil
+	}
+
+	switch f.Operator {
+	case api.OpAnd, api.OpOr:
+		return compileList(f)
+	case api.OpNot:
+		return compileNot(f)
+	}
+
+	if f.Column == "" {
+		return nil, fmt.Errorf("operator %q requires a column: %w", f.Operator, api.ErrInvalidFilter)
+	}
+	col := goqu.I(f.Column)
+
+	switch f.Operator {
+	case api.OpEq:
+		return col.Eq(f.Value), nil
+	case api.OpNe:
+		return col.Neq(f.Value), nil
+	case api.OpGt:
+		return col.Gt(f.Value), nil
+	case api.OpGte:
+		return col.Gte(f.Value), nil
+	case api.OpLt:
+		return col.Lt(f.Value), nil
+	case api.OpLte:
+		return col.Lte(f.Value), nil
+	case api.OpIn, api.OpNotIn:
+		return compileIn(col, f)
+	case api.OpLike:
+		return col.Like(f.Value), nil
+	case api.OpIsNull:
+		// goqu binds NULL as a placeholder in prepared mode, which postgres rejects after IS
+		return goqu.L("(? IS NULL)", col), nil
+	case api.OpIsNotNull:
+		return goqu.L("(? IS NOT NULL)", col), nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %q: %w", f.Operator, api.ErrInvalidFilter)
+}
+
+func compileList(f api.Filter) (exp.Expression, error) {
+	exprs := make([]exp.Expression, 0, len(f.Filters))
+	matchesAll := false
+	for _, nested := range f.Filters {
+		e, err := Compile(nested)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			exprs = append(exprs, e)
+		} else {
+			matchesAll = true
+		}
+	}
+
+	switch {
+	case len(f.Filters) == 0 && f.Operator == api.OpOr:
+		// an empty disjunction is false, like an empty In
+		return matchNone, nil
+	case matchesAll && f.Operator == api.OpOr:
+		// a zero filter matches all records, and so does any Or containing one
+		return nil, nil
+	case len(exprs) == 0:
+		return nil, nil
+	case len(exprs) == 1:
+		return exprs[0], nil
+	case f.Operator == api.OpOr:
+		return goqu.Or(exprs...), nil
+	default:
+		return goqu.And(exprs...), nil
+	}
+}
+
+func compileNot(f api.Filter) (exp.Expression, error) {
+	if len(f.Filters) != 1 {
+		return nil, fmt.Errorf("operator %q requires exactly one nested filter: %w",
+			f.Operator, api.ErrInvalidFilter)
+	}
+
+	e, err := Compile(f.Filters[0])
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return matchNone, nil
+	}
+
+	return goqu.L("NOT ?", e), nil
+}
+
+func compileIn(col exp.IdentifierExpression, f api.Filter) (exp.Expression, error) {
+	values, ok := f.Value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("operator %q requires a list of values, got %T: %w",
+			f.Operator, f.Value, api.ErrInvalidFilter)
+	}
+
+	if len(values) == 0 {
+		if f.Operator == api.OpNotIn {
+			return matchAll, nil
+		}
+		return matchNone, nil
+	}
+
+	if f.Operator == api.OpNotIn {
+		return col.NotIn(values...), nil
+	}
+	return col.In(values...), nil
+}
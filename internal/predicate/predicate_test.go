@@ -0,0 +1,125 @@
+package predicate_test
+
+import (
+	"testing"
+
+	"github.com/Klojer/sqlcredo/internal/predicate"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   api.Filter
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "zero filter",
+			filter:  api.Filter{},
+			wantSQL: "SELECT * FROM `t`",
+		},
+		{
+			name:     "eq",
+			filter:   api.Eq("name", "John"),
+			wantSQL:  "SELECT * FROM `t` WHERE (`name` = ?)",
+			wantArgs: []any{"John"},
+		},
+		{
+			name:     "and with nested or",
+			filter:   api.And(api.Gte("age", 18), api.Or(api.IsNull("email"), api.Like("email", "%@x.com"))),
+			wantSQL:  "SELECT * FROM `t` WHERE ((`age` >= ?) AND ((`email` IS NULL) OR (`email` LIKE ?)))",
+			wantArgs: []any{int64(18), "%@x.com"},
+		},
+		{
+			name:     "and skips zero filters",
+			filter:   api.And(api.Filter{}, api.Ne("id", "1")),
+			wantSQL:  "SELECT * FROM `t` WHERE (`id` != ?)",
+			wantArgs: []any{"1"},
+		},
+		{
+			name:    "or with zero filter matches everything",
+			filter:  api.Or(api.Filter{}, api.Ne("id", "1")),
+			wantSQL: "SELECT * FROM `t`",
+		},
+		{
+			name:    "empty or matches nothing",
+			filter:  api.Or(),
+			wantSQL: "SELECT * FROM `t` WHERE 1 = 0",
+		},
+		{
+			name:     "and with nested or with zero filter",
+			filter:   api.And(api.Or(api.Eq("id", "1"), api.And()), api.Eq("name", "John")),
+			wantSQL:  "SELECT * FROM `t` WHERE (`name` = ?)",
+			wantArgs: []any{"John"},
+		},
+		{
+			name:     "in",
+			filter:   api.In("id", "1", "2"),
+			wantSQL:  "SELECT * FROM `t` WHERE (`id` IN (?, ?))",
+			wantArgs: []any{"1", "2"},
+		},
+		{
+			name:    "empty in matches nothing",
+			filter:  api.In("id"),
+			wantSQL: "SELECT * FROM `t` WHERE 1 = 0",
+		},
+		{
+			name:    "empty not in matches everything",
+			filter:  api.NotIn("id"),
+			wantSQL: "SELECT * FROM `t` WHERE 1 = 1",
+		},
+		{
+			name:     "not",
+			filter:   api.Not(api.Eq("id", "1")),
+			wantSQL:  "SELECT * FROM `t` WHERE NOT (`id` = ?)",
+			wantArgs: []any{"1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := predicate.Compile(tt.filter)
+			require.NoError(t, err)
+
+			builder := goqu.Dialect("sqlite3").From("t").Prepared(true)
+			if expr != nil {
+				builder = builder.Where(expr)
+			}
+			gotSQL, gotArgs, err := builder.ToSQL()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantSQL, gotSQL)
+			if tt.wantArgs == nil {
+				assert.Empty(t, gotArgs)
+			} else {
+				assert.Equal(t, tt.wantArgs, gotArgs)
+			}
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter api.Filter
+	}{
+		{name: "unknown operator", filter: api.Filter{Operator: "between", Column: "a"}},
+		{name: "missing column", filter: api.Eq("", 1)},
+		{name: "in without list", filter: api.Filter{Operator: api.OpIn, Column: "a", Value: 1}},
+		{name: "nested invalid", filter: api.Or(api.Eq("a", 1), api.Filter{Operator: "??"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := predicate.Compile(tt.filter)
+
+			assert.ErrorIs(t, err, api.ErrInvalidFilter)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package relation
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/Klojer/sqlcredo/internal/structmap"
+)
+
+// Keys returns the distinct non-NULL values of column over records,
+// a slice of structs or struct pointers.
+func Keys(records reflect.Value, column string) ([]any, error) {
+	keyField, err := columnField(records.Type().Elem(), column)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[any]struct{}{}
+	keys := make([]any, 0, records.Len())
+	for i := 0; i < records.Len(); i++ {
+		record, ok := deref(records.Index(i))
+		if !ok {
+			continue
+		}
+
+		value, key, ok := keyOf(record.FieldByIndex(keyField.Index))
+		if !ok {
+			continue
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, value)
+	}
+
+	return keys, nil
+}
+
+// AssignMany sets field of every parent to the children whose childColumn
+// matches the parent's parentColumn. The field must be a slice of the child type
+// or of pointers to it.
+func AssignMany(parents reflect.Value, field string, parentColumn string,
+	children reflect.Value, childColumn string,
+) error {
+	target, err := relationField(parents.Type().Elem(), field)
+	if err != nil {
+		return err
+	}
+	if target.Type.Kind() != reflect.Slice || !elemOf(target.Type.Elem(), children.Type().Elem()) {
+		return fmt.Errorf("field %q of type %s cannot hold %s", field, target.Type, children.Type())
+	}
+
+	groups, err := group(children, childColumn)
+	if err != nil {
+		return err
+	}
+
+	return forEachParent(parents, parentColumn, func(parent reflect.Value, key any) {
+		matched := groups[key]
+		slice := reflect.MakeSlice(target.Type, 0, len(matched))
+		for _, child := range matched {
+			slice = reflect.Append(slice, convert(child, target.Type.Elem()))
+		}
+		parent.FieldByIndex(target.Index).Set(slice)
+	})
+}
+
+// AssignOne sets field of every parent to the child whose childColumn
+// matches the parent's parentColumn. The field must be of the child type
+// or a pointer to it; it is left untouched if no child matches.
+func AssignOne(parents reflect.Value, field string, parentColumn string,
+	children reflect.Value, childColumn string,
+) error {
+	target, err := relationField(parents.Type().Elem(), field)
+	if err != nil {
+		return err
+	}
+	if !elemOf(target.Type, children.Type().Elem()) {
+		return fmt.Errorf("field %q of type %s cannot hold %s", field, target.Type, children.Type().Elem())
+	}
+
+	groups, err := group(children, childColumn)
+	if err != nil {
+		return err
+	}
+
+	return forEachParent(parents, parentColumn, func(parent reflect.Value, key any) {
+		matched := groups[key]
+		if len(matched) == 0 {
+			return
+		}
+		parent.FieldByIndex(target.Index).Set(convert(matched[0], target.Type))
+	})
+}
+
+func forEachParent(parents reflect.Value, column string, fn func(parent reflect.Value, key any)) error {
+	keyField, err := columnField(parents.Type().Elem(), column)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < parents.Len(); i++ {
+		parent, ok := deref(parents.Index(i))
+		if !ok {
+			continue
+		}
+		if _, key, ok := keyOf(parent.FieldByIndex(keyField.Index)); ok {
+			fn(parent, key)
+		}
+	}
+	return nil
+}
+
+func group(records reflect.Value, column string) (map[any][]reflect.Value, error) {
+	keyField, err := columnField(records.Type().Elem(), column)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[any][]reflect.Value{}
+	for i := 0; i < records.Len(); i++ {
+		record := records.Index(i)
+		value, ok := deref(record)
+		if !ok {
+			continue
+		}
+		if _, key, ok := keyOf(value.FieldByIndex(keyField.Index)); ok {
+			groups[key] = append(groups[key], record)
+		}
+	}
+	return groups, nil
+}
+
+func columnField(t reflect.Type, column string) (structmap.Field, error) {
+	f, ok := structmap.ByColumn(t, column)
+	if !ok {
+		return structmap.Field{}, fmt.Errorf("type %s has no field mapped to column %q", t, column)
+	}
+	return f, nil
+}
+
+func relationField(t reflect.Type, name string) (reflect.StructField, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	f, ok := t.FieldByName(name)
+	if !ok || !f.IsExported() {
+		return reflect.StructField{}, fmt.Errorf("type %s has no exported field %q", t, name)
+	}
+	return f, nil
+}
+
+// keyOf returns the value to query by and its normalized form usable as a map key,
+// so that e.g. a named string ID matches a *string foreign key.
+func keyOf(v reflect.Value) (any, any, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil, false
+		}
+		v = v.Elem()
+	}
+
+	value := v.Interface()
+	if valuer, ok := value.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil || dv == nil {
+			return nil, nil, false
+		}
+		return dv, dv, true
+	}
+
+	switch {
+	case v.CanInt():
+		return value, v.Int(), true
+	case v.CanUint():
+		return value, v.Uint(), true
+	case v.Kind() == reflect.String:
+		return value, v.String(), true
+	}
+	return value, value, true
+}
+
+func deref(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+func elemOf(fieldType reflect.Type, childType reflect.Type) bool {
+	return indirect(fieldType) == indirect(childType)
+}
+
+func convert(child reflect.Value, to reflect.Type) reflect.Value {
+	switch {
+	case child.Type() == to:
+		return child
+	case to.Kind() == reflect.Pointer:
+		if child.CanAddr() {
+			return child.Addr()
+		}
+		ptr := reflect.New(child.Type())
+		ptr.Elem().Set(child)
+		return ptr
+	default:
+		return child.Elem()
+	}
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,79 @@
+package relation_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/internal/relation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type identity string
+
+type parent struct {
+	ID       identity `db:"id"`
+	Children []*child `db:"-"`
+	First    child    `db:"-"`
+}
+
+type child struct {
+	ID       int     `db:"id"`
+	ParentID *string `db:"parent_id"`
+}
+
+func TestKeys(t *testing.T) {
+	p1, p2 := "p1", "p2"
+	children := []child{{ID: 1, ParentID: &p1}, {ID: 2, ParentID: &p1}, {ID: 3}, {ID: 4, ParentID: &p2}}
+
+	keys, err := relation.Keys(reflect.ValueOf(children), "parent_id")
+
+	require.NoError(t, err)
+	assert.Equal(t, []any{"p1", "p2"}, keys)
+}
+
+func TestKeys_UnknownColumn(t *testing.T) {
+	_, err := relation.Keys(reflect.ValueOf([]child{}), "unknown")
+
+	assert.Error(t, err)
+}
+
+func TestAssignMany(t *testing.T) {
+	p1 := "p1"
+	parents := []*parent{{ID: "p1"}, {ID: "p2"}, nil}
+	children := []child{{ID: 1, ParentID: &p1}, {ID: 2, ParentID: &p1}}
+
+	err := relation.AssignMany(reflect.ValueOf(parents), "Children", "id",
+		reflect.ValueOf(children), "parent_id")
+
+	require.NoError(t, err)
+	assert.Equal(t, []*child{&children[0], &children[1]}, parents[0].Children)
+	assert.Empty(t, parents[1].Children)
+}
+
+func TestAssignOne(t *testing.T) {
+	p1 := "p1"
+	parents := []parent{{ID: "p1"}, {ID: "p2"}}
+	children := []child{{ID: 1, ParentID: &p1}}
+
+	err := relation.AssignOne(reflect.ValueOf(parents), "First", "id",
+		reflect.ValueOf(children), "parent_id")
+
+	require.NoError(t, err)
+	assert.Equal(t, children[0], parents[0].First)
+	assert.Equal(t, child{}, parents[1].First)
+}
+
+func TestAssign_InvalidField(t *testing.T) {
+	parents := []parent{{ID: "p1"}}
+	children := []child{{ID: 1}}
+
+	err := relation.AssignMany(reflect.ValueOf(parents), "First", "id",
+		reflect.ValueOf(children), "parent_id")
+	assert.Error(t, err)
+
+	err = relation.AssignOne(reflect.ValueOf(parents), "Missing", "id",
+		reflect.ValueOf(children), "parent_id")
+	assert.Error(t, err)
+}
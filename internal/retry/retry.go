@@ -0,0 +1,107 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 10 * time.Millisecond
+	defaultMaxBackoff     = 500 * time.Millisecond
+)
+
+// Postgres error codes of transient failures.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// DefaultPolicy returns the retry policy for the given driver.
+// Drivers without known transient errors get a policy that never retries.
+func DefaultPolicy(driver string) api.RetryPolicy {
+	policy := api.RetryPolicy{
+		MaxAttempts:    defaultMaxAttempts,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Retryable:      func(error) bool { return false },
+	}
+
+	switch driver {
+	case "pgx", "postgres":
+		policy.Retryable = IsPostgresTransient
+	case "sqlite3":
+		policy.Retryable = IsSQLiteTransient
+	}
+	return policy
+}
+
+// IsPostgresTransient reports whether err is a serialization failure or a deadlock.
+func IsPostgresTransient(err error) bool {
+	var pgErr interface{ SQLState() string }
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	code := pgErr.SQLState()
+	return code == sqlStateSerializationFailure || code == sqlStateDeadlockDetected
+}
+
+// IsSQLiteTransient reports whether err is SQLITE_BUSY or SQLITE_LOCKED.
+// The errors are matched by message, so the driver isn't a dependency.
+func IsSQLiteTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") ||
+		strings.Contains(msg, "database table is locked")
+}
+
+// Do calls fn until it succeeds, fails with an error not retryable by the policy,
+// the attempts are exhausted or ctx is done. Retries wait for an exponential
+// backoff with full jitter.
+func Do(ctx context.Context, policy api.RetryPolicy, fn func() error) error {
+	err := fn()
+	for attempt := 1; attempt < policy.MaxAttempts && err != nil; attempt++ {
+		if policy.Retryable == nil || !policy.Retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(Backoff(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		err = fn()
+	}
+	return err
+}
+
+// Backoff returns a random wait before the given retry attempt (starting with 1),
+// up to InitialBackoff doubled on each attempt and capped by MaxBackoff.
+func Backoff(policy api.RetryPolicy, attempt int) time.Duration {
+	ceiling := policy.InitialBackoff
+	for i := 1; i < attempt && ceiling > 0; i++ {
+		ceiling *= 2
+		if policy.MaxBackoff > 0 && ceiling >= policy.MaxBackoff {
+			break
+		}
+	}
+	if policy.MaxBackoff > 0 && ceiling > policy.MaxBackoff {
+		ceiling = policy.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
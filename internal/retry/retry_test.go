@@ -0,0 +1,107 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Klojer/sqlcredo/internal/retry"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+var errTransient = errors.New("transient")
+
+func TestDo(t *testing.T) {
+	policy := api.RetryPolicy{
+		MaxAttempts: 3,
+		Retryable:   func(err error) bool { return errors.Is(err, errTransient) },
+	}
+
+	calls := 0
+	err := retry.Do(context.Background(), policy, func() error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDo_Exhausted(t *testing.T) {
+	policy := api.RetryPolicy{MaxAttempts: 2, Retryable: func(error) bool { return true }}
+
+	calls := 0
+	err := retry.Do(context.Background(), policy, func() error {
+		calls++
+		return errTransient
+	})
+
+	assert.ErrorIs(t, err, errTransient)
+	assert.Equal(t, 2, calls)
+}
+
+func TestDo_NotRetryable(t *testing.T) {
+	policy := api.RetryPolicy{MaxAttempts: 5, Retryable: func(error) bool { return false }}
+
+	calls := 0
+	err := retry.Do(context.Background(), policy, func() error {
+		calls++
+		return errTransient
+	})
+
+	assert.ErrorIs(t, err, errTransient)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDo_ContextDone(t *testing.T) {
+	policy := api.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Hour,
+		Retryable:      func(error) bool { return true },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := retry.Do(ctx, policy, func() error {
+		calls++
+		return errTransient
+	})
+
+	assert.ErrorIs(t, err, errTransient)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBackoff(t *testing.T) {
+	policy := api.RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, retry.Backoff(policy, 1), 10*time.Millisecond)
+		assert.LessOrEqual(t, retry.Backoff(policy, 3), 40*time.Millisecond)
+		assert.LessOrEqual(t, retry.Backoff(policy, 10), 50*time.Millisecond)
+	}
+	assert.Zero(t, retry.Backoff(api.RetryPolicy{}, 1))
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	pgErr := fmt.Errorf("unable to exec db query: %w", &pgconn.PgError{Code: "40001"})
+	deadlock := &pgconn.PgError{Code: "40P01"}
+	uniqueViolation := &pgconn.PgError{Code: "23505"}
+	busy := fmt.Errorf("unable to exec db query: %w", sqlite3.Error{Code: sqlite3.ErrBusy})
+
+	assert.True(t, retry.DefaultPolicy("pgx").Retryable(pgErr))
+	assert.True(t, retry.DefaultPolicy("pgx").Retryable(deadlock))
+	assert.False(t, retry.DefaultPolicy("pgx").Retryable(uniqueViolation))
+	assert.True(t, retry.DefaultPolicy("sqlite3").Retryable(busy))
+	assert.False(t, retry.DefaultPolicy("sqlite3").Retryable(errTransient))
+	assert.False(t, retry.DefaultPolicy("mysql").Retryable(busy))
+}
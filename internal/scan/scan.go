@@ -0,0 +1,182 @@
+package scan
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/Klojer/sqlcredo/internal/structmap"
+)
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// maxMappers bounds the mapper cache, which raw queries with varying column
+// lists would otherwise grow without limit.
+const maxMappers = 1024
+
+// mapper maps the columns of a result set to the fields of a struct type.
+type mapper struct {
+	columns []column
+}
+
+// column is the destination field of a result column. Fields not behind
+// an embedded struct pointer are addressed by their offset in the struct,
+// so scanning a row needs no reflective field lookups.
+type column struct {
+	index  []int
+	typ    reflect.Type
+	offset uintptr
+	direct bool
+}
+
+type mapperKey struct {
+	t       reflect.Type
+	columns string
+}
+
+var (
+	mappersMu sync.RWMutex
+	mappers   = make(map[mapperKey]*mapper)
+)
+
+// Supports reports whether All can fill dest, which must be a pointer
+// to a slice of structs or struct pointers. Structs implementing sql.Scanner
+// and structs without mapped fields (like time.Time) are scanned as single
+// values and are not supported.
+func Supports(dest any) bool {
+	t := reflect.TypeOf(dest)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Slice {
+		return false
+	}
+
+	elem := t.Elem().Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct || reflect.PointerTo(elem).Implements(scannerType) {
+		return false
+	}
+	return len(structmap.Fields(elem)) > 0
+}
+
+// All appends the rows to dest, which must be supported by Supports,
+// and closes the rows. Column to field mappings are built once per struct
+// type and column set, and rows are scanned directly into the slice.
+func All(rows *sql.Rows, dest any) (err error) {
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	slice := reflect.ValueOf(dest).Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("unable to get columns: %w", err)
+	}
+
+	m, err := mapperFor(elemType, columns)
+	if err != nil {
+		return err
+	}
+
+	targets := make([]any, len(columns))
+	for rows.Next() {
+		if isPtr {
+			e := reflect.New(elemType)
+			if err := m.scan(rows, e.Elem(), targets); err != nil {
+				return err
+			}
+			slice.Set(reflect.Append(slice, e))
+			continue
+		}
+
+		// rows are scanned in place into a new element of the slice
+		n := slice.Len()
+		if n < slice.Cap() {
+			slice.SetLen(n + 1)
+			slice.Index(n).SetZero()
+		} else {
+			slice.Set(reflect.Append(slice, reflect.Zero(elemType)))
+		}
+		if err := m.scan(rows, slice.Index(n), targets); err != nil {
+			slice.SetLen(n)
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// scan scans the current row into the struct v, using targets as scratch space.
+func (m *mapper) scan(rows *sql.Rows, v reflect.Value, targets []any) error {
+	base := v.Addr().UnsafePointer()
+	for i, c := range m.columns {
+		if c.direct {
+			targets[i] = reflect.NewAt(c.typ, unsafe.Add(base, c.offset)).Interface()
+		} else {
+			targets[i] = structmap.FieldByIndex(v, c.index).Addr().Interface()
+		}
+	}
+
+	if err := rows.Scan(targets...); err != nil {
+		return fmt.Errorf("unable to scan row: %w", err)
+	}
+	return nil
+}
+
+func mapperFor(t reflect.Type, columns []string) (*mapper, error) {
+	key := mapperKey{t: t, columns: strings.Join(columns, "\x00")}
+	mappersMu.RLock()
+	m, ok := mappers[key]
+	mappersMu.RUnlock()
+	if ok {
+		return m, nil
+	}
+
+	m = &mapper{columns: make([]column, len(columns))}
+	for i, name := range columns {
+		f, ok := structmap.ByColumn(t, name)
+		if !ok {
+			return nil, fmt.Errorf("missing destination name %s in %s", name, t)
+		}
+		m.columns[i] = newColumn(t, f.Index)
+	}
+
+	mappersMu.Lock()
+	defer mappersMu.Unlock()
+	if len(mappers) >= maxMappers {
+		// evict an arbitrary mapper; map iteration order is random
+		for k := range mappers {
+			delete(mappers, k)
+			break
+		}
+	}
+	mappers[key] = m
+	return m, nil
+}
+
+// newColumn resolves the field at index of the struct type t.
+func newColumn(t reflect.Type, index []int) column {
+	c := column{index: index, direct: true}
+	for _, x := range index {
+		if t.Kind() == reflect.Pointer {
+			c.direct = false
+			t = t.Elem()
+		}
+		f := t.Field(x)
+		c.offset += f.Offset
+		t = f.Type
+	}
+	c.typ = t
+	return c
+}
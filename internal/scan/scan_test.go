@@ -0,0 +1,111 @@
+package scan_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/internal/scan"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Audit struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type testObj struct {
+	ID      int64          `db:"id"`
+	Name    string         `db:"name"`
+	Comment sql.NullString `db:"comment"`
+	*Audit
+}
+
+func TestAll(t *testing.T) {
+	db := newTestDB(t)
+
+	rows, err := db.Query("SELECT id, name, comment, created_by FROM test_table ORDER BY id")
+	require.NoError(t, err)
+
+	var dest []testObj
+	require.NoError(t, scan.All(rows, &dest))
+
+	require.Len(t, dest, 2)
+	assert.Equal(t, int64(1), dest[0].ID)
+	assert.Equal(t, "first", dest[0].Name)
+	assert.Equal(t, sql.NullString{String: "note", Valid: true}, dest[0].Comment)
+	assert.Equal(t, "admin", dest[0].CreatedBy)
+	assert.False(t, dest[1].Comment.Valid)
+}
+
+func TestAll_Pointers(t *testing.T) {
+	db := newTestDB(t)
+
+	rows, err := db.Query("SELECT name, id FROM test_table ORDER BY id DESC")
+	require.NoError(t, err)
+
+	var dest []*testObj
+	require.NoError(t, scan.All(rows, &dest))
+
+	require.Len(t, dest, 2)
+	assert.Equal(t, &testObj{ID: 2, Name: "second"}, dest[0])
+}
+
+func TestAll_Append(t *testing.T) {
+	db := newTestDB(t)
+
+	rows, err := db.Query("SELECT id, name FROM test_table ORDER BY id")
+	require.NoError(t, err)
+
+	// the spare capacity holds stale elements, which must not leak into scanned rows
+	stale := testObj{ID: 9, Name: "stale", Comment: sql.NullString{String: "x", Valid: true}}
+	buf := []testObj{{ID: 7, Name: "existing"}, stale, stale}
+	dest := buf[:1]
+	require.NoError(t, scan.All(rows, &dest))
+
+	assert.Equal(t, []testObj{{ID: 7, Name: "existing"}, {ID: 1, Name: "first"}, {ID: 2, Name: "second"}}, dest)
+}
+
+func TestAll_MissingDestination(t *testing.T) {
+	db := newTestDB(t)
+
+	rows, err := db.Query("SELECT id, name AS title FROM test_table")
+	require.NoError(t, err)
+
+	var dest []testObj
+	err = scan.All(rows, &dest)
+
+	assert.ErrorContains(t, err, "missing destination name title")
+}
+
+func TestSupports(t *testing.T) {
+	assert.True(t, scan.Supports(&[]testObj{}))
+	assert.True(t, scan.Supports(&[]*testObj{}))
+	assert.False(t, scan.Supports([]testObj{}))
+	assert.False(t, scan.Supports(&[]string{}))
+	assert.False(t, scan.Supports(&[]sql.NullString{}))
+	assert.False(t, scan.Supports(&testObj{}))
+	assert.False(t, scan.Supports(nil))
+}
+
+func newTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(tb, err)
+	db.SetMaxOpenConns(1)
+	tb.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE test_table (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		comment TEXT,
+		created_by TEXT NOT NULL
+	);
+	INSERT INTO test_table VALUES (1, 'first', 'note', 'admin'), (2, 'second', NULL, '')`)
+	require.NoError(tb, err)
+
+	return db
+}
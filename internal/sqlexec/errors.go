@@ -0,0 +1,32 @@
+package sqlexec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// Postgres error code of unique constraint violations.
+const sqlStateUniqueViolation = "23505"
+
+// mapError adds the api error matching a driver error to its chain.
+// Drivers are matched by error interfaces and messages, so they aren't dependencies.
+func mapError(err error) error {
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %w", api.ErrDuplicateKey, err)
+	}
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		return pgErr.SQLState() == sqlStateUniqueViolation
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") ||
+		strings.Contains(msg, "PRIMARY KEY constraint failed")
+}
@@ -0,0 +1,107 @@
+package sqlexec_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLExecutor_Replicas(t *testing.T) {
+	c, ctx := newTestCase(t)
+	first, firstMock := newReplica(t)
+	second, secondMock := newReplica(t)
+	c.UnderTest.SetReplicas(first, second)
+
+	query := "SELECT name FROM users"
+	firstMock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("first"))
+	secondMock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("second"))
+	firstMock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("first"))
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		var name string
+		require.NoError(t, c.UnderTest.SelectOne(ctx, &name, query))
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{"first", "second", "first"}, names)
+
+	assert.NoError(t, firstMock.ExpectationsWereMet())
+	assert.NoError(t, secondMock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_Replicas_Primary(t *testing.T) {
+	c, ctx := newTestCase(t)
+	replica, replicaMock := newReplica(t)
+	c.UnderTest.SetReplicas(replica)
+
+	c.Mock.ExpectExec("INSERT INTO users").WithArgs("John").WillReturnResult(sqlmock.NewResult(1, 1))
+	c.Mock.ExpectQuery("SELECT name FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+	c.Mock.ExpectBegin()
+
+	_, err := c.UnderTest.Exec(ctx, "INSERT INTO users (name) VALUES (?)", "John")
+	require.NoError(t, err)
+	var names []string
+	require.NoError(t, c.UnderTest.SelectMany(api.WithPrimary(ctx), &names, "SELECT name FROM users"))
+	assert.Equal(t, []string{"John"}, names)
+	_, err = c.UnderTest.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_ReplicaPicker(t *testing.T) {
+	c, ctx := newTestCase(t)
+	first, firstMock := newReplica(t)
+	second, secondMock := newReplica(t)
+	c.UnderTest.SetReplicas(first, second)
+	c.UnderTest.SetReplicaPicker(func(_ context.Context, n int) int { return n - 1 })
+
+	secondMock.ExpectQuery("SELECT name FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("second"))
+
+	var name string
+	require.NoError(t, c.UnderTest.SelectOne(ctx, &name, "SELECT name FROM users"))
+	assert.Equal(t, "second", name)
+
+	assert.NoError(t, firstMock.ExpectationsWereMet())
+	assert.NoError(t, secondMock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_ReplicaPicker_OutOfRange(t *testing.T) {
+	c, ctx := newTestCase(t)
+	replica, replicaMock := newReplica(t)
+	c.UnderTest.SetReplicas(replica)
+
+	for _, index := range []int{-1, 1} {
+		c.UnderTest.SetReplicaPicker(func(context.Context, int) int { return index })
+		c.Mock.ExpectQuery("SELECT name FROM users").
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("primary"))
+
+		var name string
+		require.NoError(t, c.UnderTest.SelectOne(ctx, &name, "SELECT name FROM users"))
+		assert.Equal(t, "primary", name)
+	}
+
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func newReplica(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return sqlx.NewDb(db, "sqlmock"), mock
+}
@@ -0,0 +1,117 @@
+package sqlexec_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLExecutor_Retry_Read(t *testing.T) {
+	c, ctx := newDriverTestCase(t, "pgx")
+
+	query := "SELECT name FROM users"
+	c.Mock.ExpectQuery(query).WillReturnError(&pgconn.PgError{Code: "40001"})
+	c.Mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+
+	var names []string
+	require.NoError(t, c.UnderTest.SelectMany(ctx, &names, query))
+	assert.Equal(t, []string{"John"}, names)
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_Retry_ExecNotRetried(t *testing.T) {
+	c, ctx := newDriverTestCase(t, "pgx")
+
+	c.Mock.ExpectExec("UPDATE users").WillReturnError(&pgconn.PgError{Code: "40001"})
+
+	_, err := c.UnderTest.Exec(ctx, "UPDATE users SET name = $1", "John")
+	assert.Error(t, err)
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_InTx(t *testing.T) {
+	c, ctx := newTestCase(t)
+
+	c.Mock.ExpectBegin()
+	c.Mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+	c.Mock.ExpectCommit()
+
+	err := c.UnderTest.InTx(ctx, nil, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO users (name) VALUES (?)", "John")
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_InTx_Rollback(t *testing.T) {
+	c, ctx := newTestCase(t)
+
+	c.Mock.ExpectBegin()
+	c.Mock.ExpectRollback()
+
+	errFailed := errors.New("failed")
+	err := c.UnderTest.InTx(ctx, nil, func(context.Context, *sql.Tx) error {
+		return errFailed
+	})
+
+	assert.ErrorIs(t, err, errFailed)
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_InTx_Retry(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.SetRetryPolicy(api.RetryPolicy{
+		MaxAttempts: 2,
+		Retryable:   func(err error) bool { return errors.Is(err, errSerialization) },
+	})
+
+	c.Mock.ExpectBegin()
+	c.Mock.ExpectRollback()
+	c.Mock.ExpectBegin()
+	c.Mock.ExpectCommit()
+
+	calls := 0
+	err := c.UnderTest.InTx(ctx, nil, func(context.Context, *sql.Tx) error {
+		calls++
+		if calls == 1 {
+			return errSerialization
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+var errSerialization = errors.New("serialization failure")
+
+func TestSQLExecutor_Exec_DuplicateKey(t *testing.T) {
+	c, ctx := newDriverTestCase(t, "pgx")
+
+	c.Mock.ExpectExec("INSERT INTO users").WillReturnError(&pgconn.PgError{Code: "23505"})
+	c.Mock.ExpectExec("INSERT INTO users").WillReturnError(errors.New("UNIQUE constraint failed: users.id"))
+	c.Mock.ExpectExec("INSERT INTO users").WillReturnError(&pgconn.PgError{Code: "23503"})
+
+	_, err := c.UnderTest.Exec(ctx, "INSERT INTO users (id) VALUES ($1)", "u1")
+	assert.ErrorIs(t, err, api.ErrDuplicateKey)
+	var pgErr *pgconn.PgError
+	assert.ErrorAs(t, err, &pgErr)
+
+	_, err = c.UnderTest.Exec(ctx, "INSERT INTO users (id) VALUES ($1)", "u1")
+	assert.ErrorIs(t, err, api.ErrDuplicateKey)
+
+	_, err = c.UnderTest.Exec(ctx, "INSERT INTO users (id) VALUES ($1)", "u1")
+	assert.NotErrorIs(t, err, api.ErrDuplicateKey)
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
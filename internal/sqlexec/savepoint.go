@@ -0,0 +1,77 @@
+package sqlexec
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+var savepointName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Savepoint creates a savepoint with the given name in tx.
+func Savepoint(ctx context.Context, tx *sql.Tx, name string) error {
+	return execSavepoint(ctx, tx, "SAVEPOINT ", name)
+}
+
+// RollbackTo rolls tx back to the savepoint with the given name.
+// The savepoint stays active.
+func RollbackTo(ctx context.Context, tx *sql.Tx, name string) error {
+	return execSavepoint(ctx, tx, "ROLLBACK TO SAVEPOINT ", name)
+}
+
+// Release releases the savepoint with the given name, keeping its changes in tx.
+func Release(ctx context.Context, tx *sql.Tx, name string) error {
+	return execSavepoint(ctx, tx, "RELEASE SAVEPOINT ", name)
+}
+
+func execSavepoint(ctx context.Context, tx *sql.Tx, statement string, name string) error {
+	// savepoint names can't be bound as parameters
+	if !savepointName.MatchString(name) {
+		return fmt.Errorf("%w: %q", api.ErrInvalidSavepoint, name)
+	}
+
+	if _, err := tx.ExecContext(ctx, statement+name); err != nil {
+		return fmt.Errorf("unable to exec %q: %w", statement+name, err)
+	}
+	return nil
+}
+
+type savepointDepthKey struct{}
+
+// InSavepoint runs fn within a savepoint of tx, named after its nesting depth
+// in ctx. The savepoint is released if fn succeeds and rolled back otherwise.
+func InSavepoint(ctx context.Context, tx *sql.Tx, fn func(ctx context.Context) error) (err error) {
+	depth, _ := ctx.Value(savepointDepthKey{}).(int)
+	depth++
+	name := fmt.Sprintf("sqlcredo_sp_%d", depth)
+
+	if err := Savepoint(ctx, tx, name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollbackSavepoint(ctx, tx, name)
+			panic(p)
+		}
+		if err != nil {
+			rollbackSavepoint(ctx, tx, name)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, savepointDepthKey{}, depth)); err != nil {
+		return err
+	}
+
+	return Release(ctx, tx, name)
+}
+
+// rollbackSavepoint discards the changes since the savepoint and removes it.
+func rollbackSavepoint(ctx context.Context, tx *sql.Tx, name string) {
+	if err := RollbackTo(ctx, tx, name); err == nil {
+		_ = Release(ctx, tx, name)
+	}
+}
@@ -0,0 +1,77 @@
+package sqlexec_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/internal/sqlexec"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type benchObj struct {
+	ID    int64  `db:"id"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+	Age   int    `db:"age"`
+}
+
+// BenchmarkSelectMany compares SelectMany, which scans struct slices with
+// cached column mappers, against sqlx scanning of the same result set on sqlite.
+func BenchmarkSelectMany(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		db := newBenchDB(b, n)
+		executor := sqlexec.NewSQLExecutor(db)
+		query := "SELECT id, name, email, age FROM bench_table"
+		ctx := context.Background()
+
+		b.Run(fmt.Sprintf("rows=%d/SelectMany", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var dest []benchObj
+				if err := executor.SelectMany(ctx, &dest, query); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("rows=%d/sqlx", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var dest []benchObj
+				if err := db.SelectContext(ctx, &dest, query); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func newBenchDB(b *testing.B, n int) *sqlx.DB {
+	b.Helper()
+
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(b, err)
+	sqlDB.SetMaxOpenConns(1)
+	b.Cleanup(func() { _ = sqlDB.Close() })
+
+	db := sqlx.NewDb(sqlDB, "sqlite3")
+	_, err = db.Exec(`CREATE TABLE bench_table (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL,
+		age INTEGER NOT NULL
+	)`)
+	require.NoError(b, err)
+
+	for i := 0; i < n; i++ {
+		_, err := db.Exec("INSERT INTO bench_table (name, email, age) VALUES (?, ?, ?)",
+			fmt.Sprintf("name%d", i), fmt.Sprintf("user%d@example.com", i), i%100)
+		require.NoError(b, err)
+	}
+	return db
+}
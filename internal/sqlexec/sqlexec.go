@@ -4,30 +4,181 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 
+	"github.com/Klojer/sqlcredo/internal/retry"
+	"github.com/Klojer/sqlcredo/internal/scan"
 	"github.com/Klojer/sqlcredo/pkg/api"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type SQLExecutor struct {
-	db        *sqlx.DB
+	primary   *conn
+	replicas  []*conn
+	picker    api.ReplicaPicker
+	retry     api.RetryPolicy
+	timeouts  api.Timeouts
+	ambientTx bool
 	DebugFunc api.DebugFunc
 }
 
-var _ api.SQLExecutor = &SQLExecutor{}
+// conn is a database with its own prepared statement cache,
+// as prepared statements are bound to a database.
+type conn struct {
+	db    *sqlx.DB
+	stmts *stmtCache
+}
+
+var (
+	_ api.SQLExecutor      = &SQLExecutor{}
+	_ api.NamedSQLExecutor = &SQLExecutor{}
+)
 
 func NewSQLExecutor(db *sqlx.DB) *SQLExecutor {
 	return &SQLExecutor{
-		db:        db,
+		primary:   &conn{db: db},
+		picker:    api.RoundRobin(),
+		retry:     retry.DefaultPolicy(db.DriverName()),
 		DebugFunc: func(sql string, args ...any) {},
 	}
 }
 
+// DriverName returns the name of the driver the executor was created with.
+func (r *SQLExecutor) DriverName() string {
+	return r.primary.db.DriverName()
+}
+
+// SetReplicas routes SelectOne and SelectMany to the given read replicas,
+// chosen by the replica picker. Exec and BeginTx always use the primary.
+// No replicas means all queries use the primary.
+func (r *SQLExecutor) SetReplicas(replicas ...*sqlx.DB) {
+	for _, c := range r.replicas {
+		c.disableStatementCache()
+	}
+
+	capacity := 0
+	if r.primary.stmts != nil {
+		capacity = r.primary.stmts.capacity
+	}
+
+	r.replicas = make([]*conn, 0, len(replicas))
+	for _, db := range replicas {
+		c := &conn{db: db}
+		c.enableStatementCache(capacity)
+		r.replicas = append(r.replicas, c)
+	}
+}
+
+// SetReplicaPicker sets the strategy choosing the replica for each read.
+// A nil picker restores the default round-robin.
+func (r *SQLExecutor) SetReplicaPicker(picker api.ReplicaPicker) {
+	if picker == nil {
+		picker = api.RoundRobin()
+	}
+	r.picker = picker
+}
+
+// SetRetryPolicy sets the policy retrying reads and transactions run with InTx.
+func (r *SQLExecutor) SetRetryPolicy(policy api.RetryPolicy) {
+	r.retry = policy
+}
+
+// SetTimeouts sets the default timeouts per query kind, applied to queries
+// whose context has no deadline. The kind is taken from the context
+// (see api.WithQueryKind), defaulting to read for selects and write for Exec.
+func (r *SQLExecutor) SetTimeouts(timeouts api.Timeouts) {
+	r.timeouts = timeouts
+}
+
+// SetAmbientTx makes SelectOne, SelectMany and Exec run in the transaction
+// of the context (see api.WithTxContext), and InTx create a savepoint instead
+// of a new transaction when the context already has one.
+// Queries in an ambient transaction are neither retried nor use the statement cache.
+func (r *SQLExecutor) SetAmbientTx(enabled bool) {
+	r.ambientTx = enabled
+}
+
+// EnableStatementCache makes the executor reuse prepared statements for
+// up to size distinct queries per database, evicting the least recently used ones.
+// Cached statements are prepared on the database, not on a transaction, so
+// queries run in an ambient transaction bypass the cache.
+// A size of zero or less disables the cache.
+func (r *SQLExecutor) EnableStatementCache(size int) {
+	for _, c := range r.conns() {
+		c.disableStatementCache()
+		c.enableStatementCache(size)
+	}
+}
+
+// StatementCacheStats returns the counters of the prepared statement cache,
+// summed over the primary and the replicas.
+// Returns zero stats if the cache is disabled.
+func (r *SQLExecutor) StatementCacheStats() api.StatementCacheStats {
+	var res api.StatementCacheStats
+	for _, c := range r.conns() {
+		if c.stmts == nil {
+			continue
+		}
+		stats := c.stmts.stats()
+		res.Size += stats.Size
+		res.Hits += stats.Hits
+		res.Misses += stats.Misses
+		res.Evictions += stats.Evictions
+	}
+	return res
+}
+
+func (r *SQLExecutor) conns() []*conn {
+	return append([]*conn{r.primary}, r.replicas...)
+}
+
+// reader returns the connection serving reads with ctx.
+// An index out of range returned by the picker falls back to the primary.
+func (r *SQLExecutor) reader(ctx context.Context) *conn {
+	if len(r.replicas) == 0 || api.UsePrimary(ctx) {
+		return r.primary
+	}
+	i := r.picker(ctx, len(r.replicas))
+	if i < 0 || i >= len(r.replicas) {
+		return r.primary
+	}
+	return r.replicas[i]
+}
+
+func (c *conn) enableStatementCache(size int) {
+	if size > 0 {
+		c.stmts = newStmtCache(c.db, size)
+	}
+}
+
+func (c *conn) disableStatementCache() {
+	if c.stmts != nil {
+		c.stmts.close()
+		c.stmts = nil
+	}
+}
+
 func (r *SQLExecutor) SelectOne(ctx context.Context, dest any, query string, args ...any) error {
 	r.DebugFunc(query, args...)
 
-	if err := r.db.GetContext(ctx, dest, query, args...); err != nil {
+	ctx, cancel := r.withTimeout(ctx, api.QueryRead)
+	defer cancel()
+
+	if tx, ok := r.txFromContext(ctx); ok {
+		if err := tx.GetContext(ctx, dest, query, args...); err != nil {
+			return fmt.Errorf("unable to get data from db: %w", err)
+		}
+		return nil
+	}
+
+	err := retry.Do(ctx, r.retry, func() error {
+		c := r.reader(ctx)
+		return c.run(ctx, query,
+			func(stmt *sqlx.Stmt) error { return stmt.GetContext(ctx, dest, args...) },
+			func() error { return c.db.GetContext(ctx, dest, query, args...) })
+	})
+	if err != nil {
 		return fmt.Errorf("unable to get data from db: %w", err)
 	}
 
@@ -37,24 +188,244 @@ func (r *SQLExecutor) SelectOne(ctx context.Context, dest any, query string, arg
 func (r *SQLExecutor) SelectMany(ctx context.Context, dest any, query string, args ...any) error {
 	r.DebugFunc(query, args...)
 
-	if err := r.db.SelectContext(ctx, dest, query, args...); err != nil {
+	ctx, cancel := r.withTimeout(ctx, api.QueryRead)
+	defer cancel()
+
+	if tx, ok := r.txFromContext(ctx); ok {
+		if err := selectInto(ctx, tx, dest, query, args...); err != nil {
+			return fmt.Errorf("unable to select data from db: %w", err)
+		}
+		return nil
+	}
+
+	// rows scanned by a failed attempt are dropped before retrying
+	reset := func() {}
+	if v := reflect.ValueOf(dest); v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Slice {
+		n := v.Elem().Len()
+		reset = func() { v.Elem().SetLen(n) }
+	}
+
+	err := retry.Do(ctx, r.retry, func() error {
+		reset()
+		return r.selectMany(ctx, r.reader(ctx), dest, query, args...)
+	})
+	if err != nil {
 		return fmt.Errorf("unable to select data from db: %w", err)
 	}
 
 	return nil
 }
 
+func (r *SQLExecutor) selectMany(ctx context.Context, c *conn, dest any, query string, args ...any) error {
+	if !scan.Supports(dest) {
+		return c.run(ctx, query,
+			func(stmt *sqlx.Stmt) error { return stmt.SelectContext(ctx, dest, args...) },
+			func() error { return selectInto(ctx, c.db, dest, query, args...) })
+	}
+
+	return c.run(ctx, query,
+		func(stmt *sqlx.Stmt) error {
+			rows, err := stmt.QueryContext(ctx, args...)
+			if err != nil {
+				return err
+			}
+			return scan.All(rows, dest)
+		},
+		func() error { return selectInto(ctx, c.db, dest, query, args...) })
+}
+
+// selectInto runs the query with q and scans all rows into dest.
+// Struct slices are scanned with cached column mappers.
+func selectInto(ctx context.Context, q sqlx.QueryerContext, dest any, query string, args ...any) error {
+	if !scan.Supports(dest) {
+		return sqlx.SelectContext(ctx, q, dest, query, args...)
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return scan.All(rows, dest)
+}
+
 func (r *SQLExecutor) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
 	r.DebugFunc(query, args...)
 
-	res, err := r.db.ExecContext(ctx, query, args...)
+	ctx, cancel := r.withTimeout(ctx, api.QueryWrite)
+	defer cancel()
+
+	if tx, ok := r.txFromContext(ctx); ok {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to exec db query: %w", mapError(err))
+		}
+		return res, nil
+	}
+
+	// argument-less statements are mostly DDL or multi-statement scripts,
+	// which can't be prepared and are not worth caching
+	run := r.primary.run
+	if len(args) == 0 {
+		run = runDirect
+	}
+
+	var res sql.Result
+	err := run(ctx, query,
+		func(stmt *sqlx.Stmt) (err error) {
+			res, err = stmt.ExecContext(ctx, args...)
+			return err
+		},
+		func() (err error) {
+			res, err = r.primary.db.ExecContext(ctx, query, args...)
+			return err
+		})
 	if err != nil {
-		return nil, fmt.Errorf("unable to exec db query: %w", err)
+		return nil, fmt.Errorf("unable to exec db query: %w", mapError(err))
 	}
 
 	return res, nil
 }
 
+// txFromContext returns the ambient transaction of ctx if ambient transactions are enabled.
+func (r *SQLExecutor) txFromContext(ctx context.Context) (*sqlx.Tx, bool) {
+	if !r.ambientTx {
+		return nil, false
+	}
+	tx, ok := api.TxFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return &sqlx.Tx{Tx: tx, Mapper: r.primary.db.Mapper}, true
+}
+
+// withTimeout applies the default timeout of the query kind of ctx,
+// or of defaultKind if ctx has none, unless ctx already has a deadline.
+func (r *SQLExecutor) withTimeout(ctx context.Context, defaultKind api.QueryKind) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	kind, ok := api.QueryKindFrom(ctx)
+	if !ok {
+		kind = defaultKind
+	}
+
+	timeout := r.timeouts.For(kind)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// run executes the query with a cached prepared statement if the cache is enabled,
+// or directly otherwise.
+func (c *conn) run(ctx context.Context, query string,
+	withStmt func(stmt *sqlx.Stmt) error, direct func() error,
+) error {
+	if c.stmts == nil {
+		return direct()
+	}
+	return c.stmts.run(ctx, query, withStmt)
+}
+
+func runDirect(_ context.Context, _ string, _ func(stmt *sqlx.Stmt) error, direct func() error) error {
+	return direct()
+}
+
+func (r *SQLExecutor) NamedSelectOne(ctx context.Context, dest any, query string, arg any) error {
+	boundQuery, args, err := r.bindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+	return r.SelectOne(ctx, dest, boundQuery, args...)
+}
+
+func (r *SQLExecutor) NamedSelectMany(ctx context.Context, dest any, query string, arg any) error {
+	boundQuery, args, err := r.bindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+	return r.SelectMany(ctx, dest, boundQuery, args...)
+}
+
+func (r *SQLExecutor) NamedExec(ctx context.Context, query string, arg any) (sql.Result, error) {
+	boundQuery, args, err := r.bindNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return r.Exec(ctx, boundQuery, args...)
+}
+
+// bindNamed replaces named parameters with positional ones, expands slice
+// arguments for IN clauses and rebinds placeholders to the driver's style.
+func (r *SQLExecutor) bindNamed(query string, arg any) (string, []any, error) {
+	boundQuery, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to bind named parameters: %w", err)
+	}
+
+	boundQuery, args, err = sqlx.In(boundQuery, args...)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to expand query arguments: %w", err)
+	}
+
+	return r.primary.db.Rebind(boundQuery), args, nil
+}
+
 func (r *SQLExecutor) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
-	return r.db.BeginTx(ctx, opts)
+	return r.primary.db.BeginTx(ctx, opts)
+}
+
+// InTx runs fn in a transaction on the primary database, committing it if fn
+// succeeds and rolling it back otherwise. The whole transaction is run again
+// if it fails with an error retryable by the retry policy, so fn must be safe
+// to call multiple times.
+//
+// With ambient transactions enabled, the context passed to fn carries the
+// transaction, and if ctx already has one, fn runs within a savepoint that
+// is rolled back if fn fails, without retries.
+func (r *SQLExecutor) InTx(ctx context.Context, opts *sql.TxOptions, fn api.TxFunc) error {
+	if tx, ok := r.txFromContext(ctx); ok {
+		return r.runSavepoint(ctx, tx.Tx, fn)
+	}
+
+	return retry.Do(ctx, r.retry, func() error {
+		return r.runTx(ctx, opts, fn)
+	})
+}
+
+func (r *SQLExecutor) runTx(ctx context.Context, opts *sql.TxOptions, fn api.TxFunc) (err error) {
+	tx, err := r.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if r.ambientTx {
+		ctx = api.WithTxContext(ctx, tx)
+	}
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// runSavepoint runs fn within a nested savepoint of tx.
+func (r *SQLExecutor) runSavepoint(ctx context.Context, tx *sql.Tx, fn api.TxFunc) error {
+	return InSavepoint(ctx, tx, func(ctx context.Context) error {
+		return fn(ctx, tx)
+	})
 }
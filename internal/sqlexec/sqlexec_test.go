@@ -40,6 +40,23 @@ func TestSQLExecutor_SelectMany(t *testing.T) {
 	assert.Equal(t, []string{"John Doe", "Jane Doe"}, names)
 }
 
+func TestSQLExecutor_SelectMany_Structs(t *testing.T) {
+	c, ctx := newTestCase(t)
+
+	query := "SELECT id, name FROM users"
+	rows := c.Mock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe").AddRow(2, "Jane Doe")
+	c.Mock.ExpectQuery(query).WillReturnRows(rows)
+
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	var users []user
+	err := c.UnderTest.SelectMany(ctx, &users, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []user{{1, "John Doe"}, {2, "Jane Doe"}}, users)
+}
+
 func TestSQLExecutor_Exec(t *testing.T) {
 	c, ctx := newTestCase(t)
 
@@ -51,6 +68,55 @@ func TestSQLExecutor_Exec(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSQLExecutor_NamedSelectMany(t *testing.T) {
+	c, ctx := newDriverTestCase(t, "pgx")
+
+	rows := c.Mock.NewRows([]string{"name"}).AddRow("John Doe")
+	c.Mock.ExpectQuery(`SELECT name FROM users WHERE age > \$1 AND id IN \(\$2, \$3\)`).
+		WithArgs(18, 1, 2).WillReturnRows(rows)
+
+	var names []string
+	err := c.UnderTest.NamedSelectMany(ctx, &names,
+		"SELECT name FROM users WHERE age > :age AND id IN (:ids)",
+		map[string]any{"age": 18, "ids": []int{1, 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"John Doe"}, names)
+}
+
+func TestSQLExecutor_NamedSelectOne(t *testing.T) {
+	c, ctx := newDriverTestCase(t, "pgx")
+
+	rows := c.Mock.NewRows([]string{"name"}).AddRow("John Doe")
+	c.Mock.ExpectQuery(`SELECT name FROM users WHERE id = \$1`).
+		WithArgs("u1").WillReturnRows(rows)
+
+	var name string
+	err := c.UnderTest.NamedSelectOne(ctx, &name,
+		"SELECT name FROM users WHERE id = :id", struct {
+			ID string `db:"id"`
+		}{ID: "u1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", name)
+}
+
+func TestSQLExecutor_NamedExec(t *testing.T) {
+	c, ctx := newDriverTestCase(t, "pgx")
+
+	c.Mock.ExpectExec(`DELETE FROM users WHERE id IN \(\$1, \$2\)`).
+		WithArgs("u1", "u2").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	_, err := c.UnderTest.NamedExec(ctx, "DELETE FROM users WHERE id IN (:ids)",
+		map[string]any{"ids": []string{"u1", "u2"}})
+	assert.NoError(t, err)
+}
+
+func TestSQLExecutor_NamedExec_MissingParam(t *testing.T) {
+	c, ctx := newTestCase(t)
+
+	_, err := c.UnderTest.NamedExec(ctx, "DELETE FROM users WHERE id = :id", map[string]any{})
+	assert.Error(t, err)
+}
+
 func TestSQLExecutor_BeginTx(t *testing.T) {
 	c, ctx := newTestCase(t)
 
@@ -70,12 +136,17 @@ type testCaseData struct {
 }
 
 func newTestCase(t *testing.T) (*testCaseData, context.Context) {
+	return newDriverTestCase(t, "sqlmock")
+}
+
+// newDriverTestCase creates a test case whose executor rebinds placeholders like the given driver.
+func newDriverTestCase(t *testing.T, driver string) (*testCaseData, context.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 
-	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	sqlxDB := sqlx.NewDb(db, driver)
 	executor := sqlexec.NewSQLExecutor(sqlxDB)
 
 	c := &testCaseData{
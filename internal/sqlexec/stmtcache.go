@@ -0,0 +1,182 @@
+package sqlexec
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// stmtCache is an LRU cache of prepared statements keyed by SQL text.
+// Statements are reference counted, so an evicted statement is closed
+// only after the last query using it completes.
+type stmtCache struct {
+	db       *sqlx.DB
+	capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+type cachedStmt struct {
+	query   string
+	stmt    *sqlx.Stmt
+	refs    int
+	evicted bool
+}
+
+func newStmtCache(db *sqlx.DB, capacity int) *stmtCache {
+	return &stmtCache{
+		db:       db,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		lru:      list.New(),
+	}
+}
+
+// acquire returns the cached statement for query, preparing it on a miss.
+// The statement must be released after use.
+func (c *stmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	if s := c.lookup(query); s != nil {
+		c.hits.Add(1)
+		return s, nil
+	}
+	c.misses.Add(1)
+
+	stmt, err := c.db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to prepare statement: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[query]; ok {
+		// prepared concurrently by another query
+		_ = stmt.Close()
+		s := el.Value.(*cachedStmt)
+		s.refs++
+		c.lru.MoveToFront(el)
+		return s, nil
+	}
+
+	s := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.entries[query] = c.lru.PushFront(s)
+	for c.lru.Len() > c.capacity {
+		c.removeLocked(c.lru.Back().Value.(*cachedStmt))
+		c.evictions.Add(1)
+	}
+
+	return s, nil
+}
+
+func (c *stmtCache) lookup(query string) *cachedStmt {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[query]
+	if !ok {
+		return nil
+	}
+
+	s := el.Value.(*cachedStmt)
+	s.refs++
+	c.lru.MoveToFront(el)
+	return s
+}
+
+func (c *stmtCache) release(s *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s.refs--
+	if s.evicted && s.refs == 0 {
+		_ = s.stmt.Close()
+	}
+}
+
+// invalidate drops a statement that failed because its connection was lost,
+// so the next acquire prepares it again.
+func (c *stmtCache) invalidate(s *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeLocked(s)
+}
+
+func (c *stmtCache) removeLocked(s *cachedStmt) {
+	if s.evicted {
+		return
+	}
+	s.evicted = true
+
+	if el, ok := c.entries[s.query]; ok && el.Value == s {
+		c.lru.Remove(el)
+		delete(c.entries, s.query)
+	}
+	if s.refs == 0 {
+		_ = s.stmt.Close()
+	}
+}
+
+func (c *stmtCache) stats() api.StatementCacheStats {
+	c.mu.Lock()
+	size := c.lru.Len()
+	c.mu.Unlock()
+
+	return api.StatementCacheStats{
+		Size:      size,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+func (c *stmtCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.lru.Len() > 0 {
+		c.removeLocked(c.lru.Back().Value.(*cachedStmt))
+	}
+}
+
+// run executes fn with the cached statement for query. If the statement's
+// connection was lost, it is prepared again and fn is retried once.
+func (c *stmtCache) run(ctx context.Context, query string, fn func(stmt *sqlx.Stmt) error) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		var s *cachedStmt
+		s, err = c.acquire(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		err = fn(s.stmt)
+		if !isConnLost(err) {
+			c.release(s)
+			return err
+		}
+
+		c.invalidate(s)
+		c.release(s)
+	}
+	return err
+}
+
+func isConnLost(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone)
+}
@@ -0,0 +1,109 @@
+package sqlexec_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLExecutor_StatementCache(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.EnableStatementCache(10)
+
+	query := "SELECT name FROM users WHERE id = ?"
+	prepared := c.Mock.ExpectPrepare(query)
+	prepared.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+	prepared.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Ann"))
+
+	var name string
+	require.NoError(t, c.UnderTest.SelectOne(ctx, &name, query, 1))
+	assert.Equal(t, "John", name)
+	require.NoError(t, c.UnderTest.SelectOne(ctx, &name, query, 2))
+	assert.Equal(t, "Ann", name)
+
+	assert.Equal(t, api.StatementCacheStats{Size: 1, Hits: 1, Misses: 1},
+		c.UnderTest.StatementCacheStats())
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_StatementCache_Eviction(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.EnableStatementCache(1)
+
+	first := "SELECT name FROM users WHERE id = ?"
+	second := "SELECT id FROM users WHERE name = ?"
+	c.Mock.ExpectPrepare(first).WillBeClosed().
+		ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+	c.Mock.ExpectPrepare(second).
+		ExpectQuery().WithArgs("John").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var name string
+	require.NoError(t, c.UnderTest.SelectOne(ctx, &name, first, 1))
+	var id int
+	require.NoError(t, c.UnderTest.SelectOne(ctx, &id, second, "John"))
+
+	assert.Equal(t, api.StatementCacheStats{Size: 1, Misses: 2, Evictions: 1},
+		c.UnderTest.StatementCacheStats())
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_StatementCache_Reprepare(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.EnableStatementCache(10)
+
+	query := "UPDATE users SET name = ? WHERE id = ?"
+	c.Mock.ExpectPrepare("UPDATE users").
+		ExpectExec().WithArgs("John", 1).WillReturnError(sql.ErrConnDone)
+	c.Mock.ExpectPrepare("UPDATE users").
+		ExpectExec().WithArgs("John", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := c.UnderTest.Exec(ctx, query, "John", 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(2), c.UnderTest.StatementCacheStats().Misses)
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_StatementCache_ExecWithoutArgs(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.EnableStatementCache(10)
+
+	c.Mock.ExpectExec("CREATE TABLE users").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := c.UnderTest.Exec(ctx, "CREATE TABLE users (id TEXT); CREATE INDEX i ON users (id);")
+	require.NoError(t, err)
+
+	assert.Equal(t, api.StatementCacheStats{}, c.UnderTest.StatementCacheStats())
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
+
+func TestSQLExecutor_StatementCache_AmbientTx(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.EnableStatementCache(10)
+	c.UnderTest.SetAmbientTx(true)
+
+	query := "SELECT name FROM users WHERE id = ?"
+	c.Mock.ExpectBegin()
+	c.Mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+	c.Mock.ExpectExec("UPDATE users SET name = ?").WithArgs("Ann").WillReturnResult(sqlmock.NewResult(0, 1))
+	c.Mock.ExpectCommit()
+
+	err := c.UnderTest.InTx(ctx, nil, func(ctx context.Context, _ *sql.Tx) error {
+		var name string
+		if err := c.UnderTest.SelectOne(ctx, &name, query, 1); err != nil {
+			return err
+		}
+		_, err := c.UnderTest.Exec(ctx, "UPDATE users SET name = ?", "Ann")
+		return err
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, api.StatementCacheStats{}, c.UnderTest.StatementCacheStats())
+	assert.NoError(t, c.Mock.ExpectationsWereMet())
+}
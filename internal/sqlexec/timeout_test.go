@@ -0,0 +1,61 @@
+package sqlexec_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLExecutor_Timeouts(t *testing.T) {
+	c, _ := newTestCase(t)
+	ctx := context.Background() // without a deadline of its own
+	c.UnderTest.SetTimeouts(api.Timeouts{Read: 10 * time.Millisecond})
+
+	c.Mock.ExpectQuery("SELECT name FROM users").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+
+	var name string
+	err := c.UnderTest.SelectOne(ctx, &name, "SELECT name FROM users")
+
+	assert.Error(t, err)
+}
+
+func TestSQLExecutor_Timeouts_QueryKind(t *testing.T) {
+	c, _ := newTestCase(t)
+	ctx := context.Background() // without a deadline of its own
+	c.UnderTest.SetTimeouts(api.Timeouts{Count: 10 * time.Millisecond})
+
+	c.Mock.ExpectQuery("SELECT name FROM users").
+		WillDelayFor(50 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+	c.Mock.ExpectQuery("SELECT COUNT").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	var name string
+	require.NoError(t, c.UnderTest.SelectOne(ctx, &name, "SELECT name FROM users"))
+	var count int
+	err := c.UnderTest.SelectOne(api.WithQueryKind(ctx, api.QueryCount), &count, "SELECT COUNT(*) FROM users")
+
+	assert.Error(t, err)
+}
+
+func TestSQLExecutor_Timeouts_Deadline(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.UnderTest.SetTimeouts(api.Timeouts{Read: 10 * time.Millisecond})
+
+	c.Mock.ExpectQuery("SELECT name FROM users").
+		WillDelayFor(50 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+
+	// the deadline of the context takes precedence
+	var name string
+	assert.NoError(t, c.UnderTest.SelectOne(ctx, &name, "SELECT name FROM users"))
+}
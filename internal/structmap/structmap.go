@@ -0,0 +1,153 @@
+package structmap
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const tagName = "db"
+
+// Field describes a struct field mapped to a database column.
+type Field struct {
+	Column string            // Column name from the db tag or the lowercased field name
+	Name   string            // Go field name
+	Index  []int             // Index path for reflect.Value.FieldByIndex
+	Type   reflect.Type      // Field type
+	Tag    reflect.StructTag // Full struct tag of the field
+}
+
+var cache sync.Map // reflect.Type -> []Field
+
+// Fields returns the column-mapped fields of t following sqlx conventions:
+// the db tag names the column, untagged fields use the lowercased field name,
+// fields tagged "-" and unexported fields are skipped, and untagged embedded
+// structs are flattened. Fields of the outer struct shadow embedded ones.
+// Pointers are dereferenced; non-struct types have no fields.
+func Fields(t reflect.Type) []Field {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if cached, ok := cache.Load(t); ok {
+		return cached.([]Field)
+	}
+
+	fields := dedupe(collect(t, nil, 0))
+	cache.Store(t, fields)
+	return fields
+}
+
+// Columns returns the column names of the fields of t in declaration order.
+func Columns(t reflect.Type) []string {
+	fields := Fields(t)
+	if fields == nil {
+		return nil
+	}
+
+	columns := make([]string, 0, len(fields))
+	for _, f := range fields {
+		columns = append(columns, f.Column)
+	}
+	return columns
+}
+
+// ByColumn returns the field of t mapped to column.
+func ByColumn(t reflect.Type, column string) (Field, bool) {
+	for _, f := range Fields(t) {
+		if f.Column == column {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+type depthField struct {
+	Field
+	depth int
+}
+
+func collect(t reflect.Type, index []int, depth int) []depthField {
+	res := make([]depthField, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup(tagName)
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+
+		if sf.Anonymous && !hasTag {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				res = append(res, collect(embedded, fieldIndex, depth+1)...)
+				continue
+			}
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		res = append(res, depthField{
+			Field: Field{
+				Column: name,
+				Name:   sf.Name,
+				Index:  fieldIndex,
+				Type:   sf.Type,
+				Tag:    sf.Tag,
+			},
+			depth: depth,
+		})
+	}
+
+	return res
+}
+
+func dedupe(fields []depthField) []Field {
+	shallowest := make(map[string]int, len(fields))
+	for _, f := range fields {
+		if d, ok := shallowest[f.Column]; !ok || f.depth < d {
+			shallowest[f.Column] = f.depth
+		}
+	}
+
+	res := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if shallowest[f.Column] != f.depth {
+			continue
+		}
+		// mark as taken so fields on the same depth are not duplicated
+		shallowest[f.Column] = -1
+		res = append(res, f.Field)
+	}
+	return res
+}
+
+// FieldByIndex is like reflect.Value.FieldByIndex, but allocates
+// nil embedded struct pointers on the way.
+func FieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
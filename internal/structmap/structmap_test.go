@@ -0,0 +1,51 @@
+package structmap_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Klojer/sqlcredo/internal/structmap"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type audit struct {
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Name      string    `db:"name"`
+}
+
+type testObj struct {
+	ID      string `db:"id"`
+	Name    string `db:"name"`
+	Comment sql.NullString
+	Ignored string `db:"-"`
+	hidden  string
+	audit
+}
+
+func TestFields(t *testing.T) {
+	fields := structmap.Fields(reflect.TypeOf(&testObj{}))
+
+	assert.Equal(t, []string{"id", "name", "comment", "created_at", "updated_at"},
+		structmap.Columns(reflect.TypeOf(testObj{})))
+	assert.Len(t, fields, 5)
+	assert.Equal(t, []int{5, 0}, fields[3].Index)
+	assert.Equal(t, reflect.TypeOf(sql.NullString{}), fields[2].Type)
+}
+
+func TestFields_NonStruct(t *testing.T) {
+	assert.Nil(t, structmap.Fields(reflect.TypeOf("")))
+	assert.Nil(t, structmap.Columns(reflect.TypeOf((*any)(nil)).Elem()))
+}
+
+func TestByColumn(t *testing.T) {
+	f, ok := structmap.ByColumn(reflect.TypeOf(testObj{}), "name")
+	assert.True(t, ok)
+	assert.Equal(t, []int{1}, f.Index)
+
+	_, ok = structmap.ByColumn(reflect.TypeOf(testObj{}), "hidden")
+	assert.False(t, ok)
+}
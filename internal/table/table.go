@@ -1,6 +1,59 @@
 package table
 
+import (
+	"context"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
 type Info struct {
 	Name     string
 	IDColumn string
+
+	// Schema qualifies the table name when set.
+	Schema string
+	// SchemaFromContext resolves the schema per query when set;
+	// an empty result falls back to Schema.
+	SchemaFromContext api.SchemaFunc
+
+	// Source replaces the table in FROM clauses when set, e.g. with an aliased subquery.
+	Source exp.Expression
+
+	Tenant Tenant
+}
+
+// SchemaName returns the schema of the table for ctx, empty for the default schema.
+func (i Info) SchemaName(ctx context.Context) string {
+	if i.SchemaFromContext != nil {
+		if s := i.SchemaFromContext(ctx); s != "" {
+			return s
+		}
+	}
+	return i.Schema
+}
+
+// Table returns the identifier of the table, qualified with the schema of ctx.
+func (i Info) Table(ctx context.Context) exp.IdentifierExpression {
+	schema := i.SchemaName(ctx)
+	if schema == "" {
+		return goqu.T(i.Name)
+	}
+	return goqu.S(schema).Table(i.Name)
+}
+
+// From returns the expression to select records from.
+func (i Info) From(ctx context.Context) exp.Expression {
+	if i.Source != nil {
+		return i.Source
+	}
+	return i.Table(ctx)
+}
+
+// Static reports whether the table is the same for all contexts,
+// so queries on it can be built once.
+func (i Info) Static() bool {
+	return i.SchemaFromContext == nil
 }
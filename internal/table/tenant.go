@@ -0,0 +1,43 @@
+package table
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// Tenant scopes the queries of a table to the tenant of the context.
+// The zero value disables scoping.
+type Tenant struct {
+	Column      string
+	FromContext api.TenantFunc
+}
+
+// Enabled reports whether queries are scoped to a tenant.
+func (t Tenant) Enabled() bool {
+	return t.Column != "" && t.FromContext != nil
+}
+
+// Value returns the tenant of ctx, failing with api.ErrMissingTenant if ctx has none.
+func (t Tenant) Value(ctx context.Context) (any, error) {
+	tenant, ok := t.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: no value for column %q", api.ErrMissingTenant, t.Column)
+	}
+	return tenant, nil
+}
+
+// Scope combines filter with the condition on the tenant of ctx.
+// Returns filter unchanged if scoping is disabled.
+func (t Tenant) Scope(ctx context.Context, filter api.Filter) (api.Filter, error) {
+	if !t.Enabled() {
+		return filter, nil
+	}
+
+	tenant, err := t.Value(ctx)
+	if err != nil {
+		return api.Filter{}, err
+	}
+	return api.And(api.Eq(t.Column, tenant), filter), nil
+}
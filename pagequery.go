@@ -0,0 +1,51 @@
+package sqlcredo
+
+import (
+	"context"
+
+	"github.com/Klojer/sqlcredo/internal/page"
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// PageQuery loads a single page of the results of an arbitrary select query,
+// giving custom repository methods the same paging contract as GetPage.
+//
+// The query is wrapped in a subquery, so sorting (api.WithSortBy), keyset cursors
+// (api.WithAfter) and filters (api.WithFilter) refer to its result columns.
+// The total is counted with SELECT COUNT(*) over the same subquery. Unlike GetPage,
+// no default sort column is applied, so pass api.WithSortBy for a stable order.
+//
+// Query arguments use the placeholder style of the driver. If executor is a SQLCredo
+// instance, identifiers are quoted for its driver; otherwise ANSI quoting is used.
+//
+// Example:
+//
+//	page, err := sqlcredo.PageQuery[User](ctx, repo,
+//		"SELECT * FROM users WHERE last_name IS NOT NULL", nil,
+//		api.WithPageSize(20), api.WithSortBy("first_name"))
+func PageQuery[T any](ctx context.Context, executor api.SQLExecutor,
+	query string, args []any, opts ...api.PageOpt,
+) (api.Page[T], error) {
+	var driver string
+	if d, ok := executor.(interface{ DriverName() string }); ok {
+		driver = d.DriverName()
+	}
+
+	var records []T
+	info, err := page.QueryInto(ctx, executor, driver, &records, query, args, opts...)
+	if err != nil {
+		return api.Page[T]{}, err
+	}
+
+	if len(records) == 0 {
+		return api.Page[T]{}, nil
+	}
+
+	return api.Page[T]{
+		Number:     info.Number,
+		Size:       info.Size,
+		Total:      info.Total,
+		TotalPages: info.TotalPages,
+		Content:    records,
+	}, nil
+}
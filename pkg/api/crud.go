@@ -5,21 +5,36 @@ import (
 	"database/sql"
 )
 
-// CRUD defines a generic interface for basic database operations.
+// Reader defines a generic interface for read-only database operations.
 // Type parameters:
 //   - T: the entity type being managed
 //   - I: the type of the entity's ID field (must be comparable)
-type CRUD[T any, I comparable] interface {
+type Reader[T any, I comparable] interface {
 	// GetAll retrieves all entities of type T from the database.
-	GetAll(ctx context.Context) ([]T, error)
+	GetAll(ctx context.Context, opts ...QueryOpt) ([]T, error)
 
 	// GetByID retrieves a single entity by its ID.
 	// Returns the zero value of T and an error if the entity is not found.
-	GetByID(ctx context.Context, id I) (T, error)
+	GetByID(ctx context.Context, id I, opts ...QueryOpt) (T, error)
 
 	// GetByIDs retrieves multiple entities by their IDs.
 	// The returned slice maintains the same order as the input IDs.
-	GetByIDs(ctx context.Context, ids []I) ([]T, error)
+	GetByIDs(ctx context.Context, ids []I, opts ...QueryOpt) ([]T, error)
+
+	// ExistsByID reports whether an entity with the given ID exists.
+	ExistsByID(ctx context.Context, id I) (bool, error)
+
+	// Exists reports whether at least one entity matches the filter.
+	// The zero filter matches any entity.
+	Exists(ctx context.Context, filter Filter) (bool, error)
+}
+
+// CRUD defines a generic interface for basic database operations.
+// Type parameters:
+//   - T: the entity type being managed
+//   - I: the type of the entity's ID field (must be comparable)
+type CRUD[T any, I comparable] interface {
+	Reader[T, I]
 
 	// Create inserts a new entity into the database.
 	// The entity pointer must not be nil.
@@ -34,4 +49,8 @@ type CRUD[T any, I comparable] interface {
 	// Update modifies an existing entity identified by its ID.
 	// The entity pointer must not be nil.
 	Update(ctx context.Context, id I, e *T) (sql.Result, error)
+
+	// Upsert inserts an entity or, if one with the same ID exists, updates it.
+	// The entity pointer must not be nil.
+	Upsert(ctx context.Context, e *T) (sql.Result, error)
 }
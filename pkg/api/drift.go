@@ -0,0 +1,41 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSchemaDrift is matched by SchemaDriftError with errors.Is.
+var ErrSchemaDrift = errors.New("schema drift")
+
+// SchemaIssue is a mismatch between an entity struct and its live table.
+type SchemaIssue struct {
+	Column  string // Column the issue is about, empty for table-wide issues
+	Message string
+}
+
+func (i SchemaIssue) String() string {
+	if i.Column == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("column %q: %s", i.Column, i.Message)
+}
+
+// SchemaDriftError lists the mismatches between an entity struct and its live table.
+type SchemaDriftError struct {
+	Table  string
+	Issues []SchemaIssue
+}
+
+func (e *SchemaDriftError) Error() string {
+	issues := make([]string, 0, len(e.Issues))
+	for _, i := range e.Issues {
+		issues = append(issues, i.String())
+	}
+	return fmt.Sprintf("%s of table %q: %s", ErrSchemaDrift, e.Table, strings.Join(issues, "; "))
+}
+
+func (e *SchemaDriftError) Unwrap() error {
+	return ErrSchemaDrift
+}
@@ -5,3 +5,35 @@ import "errors"
 // ErrInvalidPageSize is returned when a page size parameter is not a positive number.
 // This error indicates that the requested page size is invalid for pagination operations.
 var ErrInvalidPageSize = errors.New("invalid page size")
+
+// ErrInvalidFilter is returned when a Filter cannot be compiled into a SQL predicate,
+// for example because of an unknown operator or a missing column name.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ErrUnknownRelation is returned when a preloaded relation is not declared on the repository.
+var ErrUnknownRelation = errors.New("unknown relation")
+
+// ErrInvalidCursor is returned when a keyset cursor doesn't match the sort columns of a page request.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrTooManyRows is returned when a query loads more rows than the configured limit.
+var ErrTooManyRows = errors.New("too many rows")
+
+// ErrInvalidSavepoint is returned when a savepoint name is not a plain SQL identifier.
+var ErrInvalidSavepoint = errors.New("invalid savepoint name")
+
+// ErrMissingTenant is returned by tenant-scoped repositories when the context has no tenant.
+var ErrMissingTenant = errors.New("missing tenant")
+
+// ErrUnsupportedDialect is returned when DDL is generated or inspected for an unknown SQL dialect.
+var ErrUnsupportedDialect = errors.New("unsupported dialect")
+
+// ErrUnsupportedType is returned when a struct field has no column type mapping.
+var ErrUnsupportedType = errors.New("unsupported column type")
+
+// ErrUnknownColumn is returned when a column is not mapped by any field of the entity.
+var ErrUnknownColumn = errors.New("unknown column")
+
+// ErrDuplicateKey is returned when a write violates a primary key or unique constraint.
+// The driver error stays in the chain for details.
+var ErrDuplicateKey = errors.New("duplicate key")
@@ -0,0 +1,112 @@
+package api
+
+// Operator identifies the kind of predicate a Filter represents.
+type Operator string
+
+const (
+	OpAnd       Operator = "and"         // All nested filters must match
+	OpOr        Operator = "or"          // At least one nested filter must match
+	OpNot       Operator = "not"         // The single nested filter must not match
+	OpEq        Operator = "eq"          // Column is equal to the value
+	OpNe        Operator = "ne"          // Column is not equal to the value
+	OpGt        Operator = "gt"          // Column is greater than the value
+	OpGte       Operator = "gte"         // Column is greater than or equal to the value
+	OpLt        Operator = "lt"          // Column is less than the value
+	OpLte       Operator = "lte"         // Column is less than or equal to the value
+	OpIn        Operator = "in"          // Column is one of the values
+	OpNotIn     Operator = "not_in"      // Column is none of the values
+	OpLike      Operator = "like"        // Column matches the LIKE pattern
+	OpIsNull    Operator = "is_null"     // Column is NULL
+	OpIsNotNull Operator = "is_not_null" // Column is not NULL
+)
+
+// Filter is a database-agnostic predicate over entity columns.
+// Filters are built with the constructor functions (Eq, In, And, ...) and
+// compiled to a WHERE clause by the SQLCredo implementation.
+// The zero Filter matches all records.
+type Filter struct {
+	Operator Operator // Kind of the predicate
+	Column   string   // Column the predicate applies to (comparison operators only)
+	Value    any      // Operand of the comparison; a []any for In and NotIn
+	Filters  []Filter // Nested filters (And, Or and Not only)
+}
+
+// IsZero reports whether the filter is empty and therefore matches all records.
+func (f Filter) IsZero() bool {
+	return f.Operator == ""
+}
+
+// Eq matches records where column is equal to value.
+func Eq(column string, value any) Filter {
+	return Filter{Operator: OpEq, Column: column, Value: value}
+}
+
+// Ne matches records where column is not equal to value.
+func Ne(column string, value any) Filter {
+	return Filter{Operator: OpNe, Column: column, Value: value}
+}
+
+// Gt matches records where column is greater than value.
+func Gt(column string, value any) Filter {
+	return Filter{Operator: OpGt, Column: column, Value: value}
+}
+
+// Gte matches records where column is greater than or equal to value.
+func Gte(column string, value any) Filter {
+	return Filter{Operator: OpGte, Column: column, Value: value}
+}
+
+// Lt matches records where column is less than value.
+func Lt(column string, value any) Filter {
+	return Filter{Operator: OpLt, Column: column, Value: value}
+}
+
+// Lte matches records where column is less than or equal to value.
+func Lte(column string, value any) Filter {
+	return Filter{Operator: OpLte, Column: column, Value: value}
+}
+
+// In matches records where column is equal to any of the values.
+// An empty value list matches no records.
+func In(column string, values ...any) Filter {
+	return Filter{Operator: OpIn, Column: column, Value: values}
+}
+
+// NotIn matches records where column is equal to none of the values.
+// An empty value list matches all records.
+func NotIn(column string, values ...any) Filter {
+	return Filter{Operator: OpNotIn, Column: column, Value: values}
+}
+
+// Like matches records where column matches the SQL LIKE pattern.
+func Like(column string, pattern string) Filter {
+	return Filter{Operator: OpLike, Column: column, Value: pattern}
+}
+
+// IsNull matches records where column is NULL.
+func IsNull(column string) Filter {
+	return Filter{Operator: OpIsNull, Column: column}
+}
+
+// IsNotNull matches records where column is not NULL.
+func IsNotNull(column string) Filter {
+	return Filter{Operator: OpIsNotNull, Column: column}
+}
+
+// And matches records that match all of the filters.
+// Zero filters are ignored.
+func And(filters ...Filter) Filter {
+	return Filter{Operator: OpAnd, Filters: filters}
+}
+
+// Or matches records that match at least one of the filters.
+// A zero filter matches all records, so an Or containing one does too,
+// while an Or without filters matches no records.
+func Or(filters ...Filter) Filter {
+	return Filter{Operator: OpOr, Filters: filters}
+}
+
+// Not matches records that do not match the filter.
+func Not(filter Filter) Filter {
+	return Filter{Operator: OpNot, Filters: []Filter{filter}}
+}
@@ -21,6 +21,9 @@ type PageParams struct {
 	PageSize   uint     // Number of items per page
 	SortBy     []string // List of columns to sort by
 	SortDesc   bool     // If true, sort in descending order
+	After      []any    // Keyset cursor: values of the sort columns of the last seen record
+
+	QueryParams
 }
 
 // PageOpt is a function type that modifies PageParams.
@@ -71,6 +74,41 @@ func WithSortDesc(column string) PageOpt {
 	}
 }
 
+// WithAfter switches to keyset pagination: the page starts right after the record
+// with the given values of the sort columns, typically the last record of the previous page.
+// The number of values must match the number of sort columns, and the page number is ignored.
+// Keyset pagination stays fast on deep pages as the database doesn't skip rows with OFFSET.
+func WithAfter(values ...any) PageOpt {
+	return func(p *PageParams) {
+		p.After = values
+	}
+}
+
+// WithColumns limits loaded columns to the given ones.
+// Multiple calls will append to the list of columns.
+// By default, the columns mapped by the db tags of the entity type are loaded.
+func WithColumns(columns ...string) PageOpt {
+	return func(p *PageParams) {
+		p.Columns = append(p.Columns, columns...)
+	}
+}
+
+// WithFilter restricts the paged records to the ones matching the filter.
+// Multiple calls are combined with And. The page total counts only matching records.
+func WithFilter(filter Filter) PageOpt {
+	return func(p *PageParams) {
+		p.addFilter(filter)
+	}
+}
+
+// WithPreload loads the given relation fields together with the page records.
+// Multiple calls will append to the list of relations.
+func WithPreload(fields ...string) PageOpt {
+	return func(p *PageParams) {
+		p.Preload = append(p.Preload, fields...)
+	}
+}
+
 // PageResolver is an interface for retrieving paginated results of type T.
 type PageResolver[T any] interface {
 	// GetPage retrieves a single page of results based on the provided pagination options.
@@ -79,4 +117,8 @@ type PageResolver[T any] interface {
 	// Count returns the total number of items available across all pages.
 	// This is useful for calculating total pages and displaying pagination metadata.
 	Count(ctx context.Context) (uint64, error)
+
+	// CountWhere returns the number of items matching the filter.
+	// The zero filter counts all items.
+	CountWhere(ctx context.Context, filter Filter) (uint64, error)
 }
@@ -65,3 +65,38 @@ func TestWithSortDesc(t *testing.T) {
 
 	assert.True(t, params.SortDesc)
 }
+
+func TestWithColumns(t *testing.T) {
+	params := &api.PageParams{}
+
+	api.WithColumns("id")(params)
+	api.WithColumns("name", "email")(params)
+
+	assert.Equal(t, []string{"id", "name", "email"}, params.Columns)
+}
+
+func TestWithFilter(t *testing.T) {
+	params := &api.PageParams{}
+
+	api.WithFilter(api.Eq("name", "John"))(params)
+	assert.Equal(t, api.Eq("name", "John"), params.Filter)
+
+	api.WithFilter(api.IsNull("email"))(params)
+	assert.Equal(t, api.And(api.Eq("name", "John"), api.IsNull("email")), params.Filter)
+}
+
+func TestWithPreload(t *testing.T) {
+	params := &api.PageParams{}
+
+	api.WithPreload("Roles", "Addresses")(params)
+
+	assert.Equal(t, []string{"Roles", "Addresses"}, params.Preload)
+}
+
+func TestWithAfter(t *testing.T) {
+	params := &api.PageParams{}
+
+	api.WithAfter("Ann", 3)(params)
+
+	assert.Equal(t, []any{"Ann", 3}, params.After)
+}
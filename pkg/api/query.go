@@ -0,0 +1,67 @@
+package api
+
+import "context"
+
+// QueryParams defines the options shared by read operations.
+type QueryParams struct {
+	Columns []string // Columns to load; derived from the destination type if empty
+	Filter  Filter   // Additional predicate the loaded records must match
+	Preload []string // Relation fields to load together with the records
+}
+
+// QueryOpt is a function type that modifies QueryParams.
+// It follows the functional options pattern for configuring read operations.
+type QueryOpt func(*QueryParams)
+
+// Select limits loaded columns to the given ones.
+// Multiple calls will append to the list of columns.
+// By default, the columns mapped by the db tags of the destination type are loaded.
+func Select(columns ...string) QueryOpt {
+	return func(p *QueryParams) {
+		p.Columns = append(p.Columns, columns...)
+	}
+}
+
+// Where restricts loaded records to the ones matching the filter.
+// Multiple calls are combined with And.
+func Where(filter Filter) QueryOpt {
+	return func(p *QueryParams) {
+		p.addFilter(filter)
+	}
+}
+
+// Preload loads the given relation fields together with the records.
+// Relations are declared on the repository; each one is loaded with a single batched query.
+// Multiple calls will append to the list of relations.
+func Preload(fields ...string) QueryOpt {
+	return func(p *QueryParams) {
+		p.Preload = append(p.Preload, fields...)
+	}
+}
+
+func (p *QueryParams) addFilter(filter Filter) {
+	if p.Filter.IsZero() {
+		p.Filter = filter
+		return
+	}
+	p.Filter = And(p.Filter, filter)
+}
+
+// PageInfo holds the metadata of a page without its content.
+type PageInfo struct {
+	Number     uint   // Current page number (0-based)
+	Size       uint   // Number of items in the current page
+	Total      uint64 // Total number of items across all pages
+	TotalPages uint   // Total number of pages
+}
+
+// Projector is an interface for loading records into caller-provided types,
+// typically smaller summary structs mapping a subset of the table columns.
+type Projector interface {
+	// GetAllInto loads all records into dest, which must be a pointer to a slice.
+	GetAllInto(ctx context.Context, dest any, opts ...QueryOpt) error
+
+	// GetPageInto loads a single page of records into dest, which must be a pointer to a slice.
+	// Returns the metadata of the loaded page.
+	GetPageInto(ctx context.Context, dest any, opts ...PageOpt) (PageInfo, error)
+}
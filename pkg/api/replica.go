@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ReplicaPicker chooses which of n read replicas serves the next read,
+// returning an index in [0, n). Reads with an index out of range use the primary.
+type ReplicaPicker func(ctx context.Context, n int) int
+
+// RoundRobin returns a ReplicaPicker cycling through the replicas in order.
+func RoundRobin() ReplicaPicker {
+	var next atomic.Uint64
+	return func(_ context.Context, n int) int {
+		return int((next.Add(1) - 1) % uint64(n))
+	}
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a context forcing reads to the primary database,
+// e.g. to read your own writes right after they are committed.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsePrimary reports whether reads with ctx are forced to the primary database.
+func UsePrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
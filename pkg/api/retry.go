@@ -0,0 +1,22 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// RetryPolicy configures retries of operations failing with transient errors,
+// like serialization failures, deadlocks or a busy database.
+// Reads are retried individually; transactions run with a transaction helper
+// are retried as a whole by calling the transaction function again.
+type RetryPolicy struct {
+	MaxAttempts    int           // Total number of attempts; one or less disables retries
+	InitialBackoff time.Duration // Upper bound of the first backoff, doubled on each retry
+	MaxBackoff     time.Duration // Cap of the backoff; zero means no cap
+	Retryable      func(err error) bool
+}
+
+// TxFunc is a function run within a transaction. The context passed
+// to it must be used for all queries of the transaction.
+type TxFunc func(ctx context.Context, tx *sql.Tx) error
@@ -27,3 +27,29 @@ type SQLExecutor interface {
 	// Returns the transaction object and any error encountered during transaction creation.
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
 }
+
+// NamedSQLExecutor defines an interface for executing SQL operations with named parameters.
+// Queries reference parameters as :name, bound from the fields of a struct (by db tag)
+// or the keys of a map[string]any. Slice parameters are expanded for IN clauses, e.g.
+// "WHERE id IN (:ids)", and placeholders are rebound to the style of the driver,
+// so the same query text runs on every supported database.
+type NamedSQLExecutor interface {
+	// NamedSelectOne executes a query that is expected to return at most one row.
+	// It scans the resulting row into the dest parameter, which must be a pointer.
+	NamedSelectOne(ctx context.Context, dest any, query string, arg any) error
+
+	// NamedSelectMany executes a query that can return multiple rows.
+	// It scans all resulting rows into the dest parameter, which must be a pointer to a slice.
+	NamedSelectMany(ctx context.Context, dest any, query string, arg any) error
+
+	// NamedExec executes a query that doesn't return rows (like INSERT, UPDATE, DELETE).
+	NamedExec(ctx context.Context, query string, arg any) (sql.Result, error)
+}
+
+// StatementCacheStats holds the counters of a prepared statement cache.
+type StatementCacheStats struct {
+	Size      int    // Number of currently cached statements
+	Hits      uint64 // Number of queries served by a cached statement
+	Misses    uint64 // Number of queries that had to prepare a statement
+	Evictions uint64 // Number of statements evicted to respect the cache capacity
+}
@@ -0,0 +1,10 @@
+package api
+
+import "context"
+
+// TenantFunc extracts the tenant from a context, reporting false if there is none.
+type TenantFunc func(ctx context.Context) (any, bool)
+
+// SchemaFunc returns the database schema for a context, or an empty string
+// for the default schema.
+type SchemaFunc func(ctx context.Context) string
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// QueryKind classifies queries for the default timeouts.
+type QueryKind int
+
+const (
+	QueryRead  QueryKind = iota // Default for SelectOne and SelectMany
+	QueryWrite                  // Default for Exec
+	QueryCount                  // Counting queries of Count, CountWhere and paging
+	QueryDDL                    // Schema changes, e.g. InitSchema
+)
+
+// Timeouts holds the default timeouts per query kind, applied when the
+// context of a query has no deadline. A zero timeout means no default.
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+	Count time.Duration
+	DDL   time.Duration
+}
+
+// For returns the timeout for the given query kind.
+func (t Timeouts) For(kind QueryKind) time.Duration {
+	switch kind {
+	case QueryWrite:
+		return t.Write
+	case QueryCount:
+		return t.Count
+	case QueryDDL:
+		return t.DDL
+	default:
+		return t.Read
+	}
+}
+
+type queryKindKey struct{}
+
+// WithQueryKind returns a context marking its queries as the given kind.
+func WithQueryKind(ctx context.Context, kind QueryKind) context.Context {
+	return context.WithValue(ctx, queryKindKey{}, kind)
+}
+
+// QueryKindFrom returns the query kind set by WithQueryKind.
+func QueryKindFrom(ctx context.Context) (QueryKind, bool) {
+	kind, ok := ctx.Value(queryKindKey{}).(QueryKind)
+	return kind, ok
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+)
+
+type txKey struct{}
+
+// WithTxContext returns a context carrying tx as the ambient transaction.
+// Repositories with ambient transactions enabled run their queries of
+// the context in tx.
+func WithTxContext(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the ambient transaction of ctx.
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok && tx != nil
+}
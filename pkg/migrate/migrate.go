@@ -0,0 +1,329 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Klojer/sqlcredo/internal/goquext"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jmoiron/sqlx"
+)
+
+const defaultTable = "sqlcredo_migrations"
+
+// Migrator applies and reverts migrations, tracking the applied versions
+// in a bookkeeping table.
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []Migration
+	table      string
+	dialect    goqu.DialectWrapper
+}
+
+// Opt configures a Migrator.
+type Opt func(*Migrator)
+
+// WithTable sets the name of the bookkeeping table, "sqlcredo_migrations" by default.
+func WithTable(table string) Opt {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// Status describes a migration and whether it is applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // The migration changed since it was applied
+}
+
+type appliedMigration struct {
+	Version   uint64    `db:"version"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// New creates a Migrator running migrations on db with the given driver,
+// the same ones SQLCredo is created with. Migrations are sorted by version;
+// duplicate versions and version 0 fail with ErrInvalidMigration.
+func New(db *sql.DB, driver string, migrations []Migration, opts ...Opt) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, mig := range sorted {
+		if mig.Version == 0 {
+			return nil, fmt.Errorf("%w: version 0 (%s)", ErrInvalidMigration, mig.Name)
+		}
+		if i > 0 && sorted[i-1].Version == mig.Version {
+			return nil, fmt.Errorf("%w: version %d has names %q and %q",
+				ErrInvalidMigration, mig.Version, sorted[i-1].Name, mig.Name)
+		}
+	}
+
+	m := &Migrator{
+		db:         db,
+		driver:     driver,
+		migrations: sorted,
+		table:      defaultTable,
+		dialect:    goqu.Dialect(goquext.CreateDialectString(driver)),
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m, nil
+}
+
+// Up applies all pending migrations in version order, each in its own transaction,
+// and returns the applied ones. It fails with ErrChecksumMismatch without applying
+// anything if an applied migration was modified.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withConn(ctx, func(conn *sqlx.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := verify(statuses); err != nil {
+			return err
+		}
+
+		for _, s := range statuses {
+			if s.Applied {
+				continue
+			}
+			if err := m.apply(ctx, conn, s.Migration); err != nil {
+				return err
+			}
+			applied = append(applied, s.Migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the given number of most recently applied migrations, newest first,
+// and returns the reverted ones.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withConn(ctx, func(conn *sqlx.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(statuses) - 1; i >= 0 && len(reverted) < steps; i-- {
+			s := statuses[i]
+			if !s.Applied {
+				continue
+			}
+			if err := m.revert(ctx, conn, s.Migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, s.Migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status returns the status of all migrations in version order.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var res []Status
+	err := m.withConn(ctx, func(conn *sqlx.Conn) (err error) {
+		res, err = m.status(ctx, conn)
+		return err
+	})
+	return res, err
+}
+
+// Verify checks that no applied migration was modified since it was applied.
+func (m *Migrator) Verify(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	return verify(statuses)
+}
+
+func verify(statuses []Status) error {
+	for _, s := range statuses {
+		if s.Modified {
+			return fmt.Errorf("%w: version %d (%s)", ErrChecksumMismatch, s.Version, s.Name)
+		}
+	}
+	return nil
+}
+
+// withConn runs fn on a dedicated connection holding the migration lock,
+// after making sure the bookkeeping table exists.
+func (m *Migrator) withConn(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	conn, err := sqlx.NewDb(m.db, m.driver).Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	query, err := m.createTableQuery()
+	if err != nil {
+		return fmt.Errorf("unable to create 'create table' query: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("unable to create migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// lock takes an advisory lock on postgres, so concurrent instances don't
+// apply migrations at the same time. Other databases rely on transactions.
+func (m *Migrator) lock(ctx context.Context, conn *sqlx.Conn) (func(), error) {
+	if !m.isPostgres() {
+		return func() {}, nil
+	}
+
+	key := m.lockKey()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		return nil, fmt.Errorf("unable to acquire migration lock: %w", err)
+	}
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+	}, nil
+}
+
+func (m *Migrator) lockKey() int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(m.table))
+	return int64(h.Sum64())
+}
+
+func (m *Migrator) status(ctx context.Context, conn *sqlx.Conn) ([]Status, error) {
+	query, args, err := m.dialect.From(goqu.I(m.table)).
+		Select("version", "checksum", "applied_at").
+		Order(goqu.I("version").Asc()).
+		Prepared(true).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create 'select' query: %w", err)
+	}
+
+	var applied []appliedMigration
+	if err := sqlx.SelectContext(ctx, conn, &applied, query, args...); err != nil {
+		return nil, fmt.Errorf("unable to load applied migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]appliedMigration, len(applied))
+	for _, a := range applied {
+		byVersion[a.Version] = a
+	}
+
+	res := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Migration: mig}
+		if a, ok := byVersion[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = a.AppliedAt
+			s.Modified = a.Checksum != mig.Checksum()
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, mig Migration) error {
+	return m.inTx(ctx, conn, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+			return fmt.Errorf("unable to apply migration %d (%s): %w", mig.Version, mig.Name, err)
+		}
+
+		query, args, err := m.dialect.Insert(goqu.I(m.table)).
+			Rows(goqu.Record{
+				"version":    mig.Version,
+				"name":       mig.Name,
+				"checksum":   mig.Checksum(),
+				"applied_at": time.Now().UTC(),
+			}).
+			Prepared(true).
+			ToSQL()
+		if err != nil {
+			return fmt.Errorf("unable to create 'insert' query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("unable to record migration %d: %w", mig.Version, err)
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sqlx.Conn, mig Migration) error {
+	if mig.Down == "" {
+		return fmt.Errorf("%w: version %d (%s)", ErrNoDownMigration, mig.Version, mig.Name)
+	}
+
+	return m.inTx(ctx, conn, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+			return fmt.Errorf("unable to revert migration %d (%s): %w", mig.Version, mig.Name, err)
+		}
+
+		query, args, err := m.dialect.Delete(goqu.I(m.table)).
+			Where(goqu.I("version").Eq(mig.Version)).
+			Prepared(true).
+			ToSQL()
+		if err != nil {
+			return fmt.Errorf("unable to create 'delete' query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("unable to record migration %d: %w", mig.Version, err)
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) inTx(ctx context.Context, conn *sqlx.Conn, fn func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) createTableQuery() (string, error) {
+	// goqu has no DDL builder, so the table is quoted by rendering a select from it
+	from, _, err := m.dialect.From(goqu.I(m.table)).ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return "CREATE TABLE IF NOT EXISTS " + strings.TrimPrefix(from, "SELECT * FROM ") + ` (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name TEXT NOT NULL,
+	checksum TEXT NOT NULL,
+	applied_at TIMESTAMP NOT NULL
+)`, nil
+}
+
+func (m *Migrator) isPostgres() bool {
+	return m.driver == "pgx" || m.driver == "postgres"
+}
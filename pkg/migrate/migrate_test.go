@@ -0,0 +1,196 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Klojer/sqlcredo/pkg/migrate"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = fstest.MapFS{
+	"migrations/1_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id TEXT PRIMARY KEY)")},
+	"migrations/1_create_users.down.sql": {Data: []byte("DROP TABLE users")},
+	"migrations/2_add_name.up.sql":       {Data: []byte("ALTER TABLE users ADD COLUMN name TEXT")},
+	"migrations/2_add_name.down.sql":     {Data: []byte("ALTER TABLE users DROP COLUMN name")},
+	"migrations/README.md":               {Data: []byte("ignored")},
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := migrate.Load(testMigrations, "migrations")
+
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, uint64(1), migrations[0].Version)
+	assert.Equal(t, "create_users", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE users", migrations[0].Down)
+	assert.Equal(t, uint64(2), migrations[1].Version)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	_, err := migrate.Load(fstest.MapFS{
+		"1_a.down.sql": {Data: []byte("DROP TABLE a")},
+	}, ".")
+	assert.ErrorIs(t, err, migrate.ErrInvalidMigration)
+
+	_, err = migrate.Load(fstest.MapFS{
+		"1_a.up.sql": {Data: []byte("CREATE TABLE a (id TEXT)")},
+		"1_b.up.sql": {Data: []byte("CREATE TABLE b (id TEXT)")},
+	}, ".")
+	assert.ErrorIs(t, err, migrate.ErrInvalidMigration)
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	migrator := newMigrator(t, db, testMigrations)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+	_, err = db.Exec("INSERT INTO users (id, name) VALUES ('1', 'John')")
+	require.NoError(t, err)
+
+	// applied migrations are skipped
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	reverted, err := migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, uint64(2), reverted[0].Version)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[0].AppliedAt.IsZero())
+	assert.False(t, statuses[1].Applied)
+}
+
+func TestMigrator_Up_FailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	migrator := newMigrator(t, db, fstest.MapFS{
+		"1_create_users.up.sql": {Data: []byte("CREATE TABLE users (id TEXT PRIMARY KEY)")},
+		"2_broken.up.sql":       {Data: []byte("CREATE TABLE broken (id TEXT); SELECT * FROM missing")},
+	})
+
+	applied, err := migrator.Up(ctx)
+
+	assert.Error(t, err)
+	require.Len(t, applied, 1)
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+	var name string
+	err = db.QueryRow("SELECT name FROM sqlite_master WHERE name = 'broken'").Scan(&name)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMigrator_ChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	_, err := newMigrator(t, db, testMigrations).Up(ctx)
+	require.NoError(t, err)
+
+	modified := fstest.MapFS{
+		"1_create_users.up.sql": {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY)")},
+		"2_add_name.up.sql":     testMigrations["migrations/2_add_name.up.sql"],
+		"3_add_email.up.sql":    {Data: []byte("ALTER TABLE users ADD COLUMN email TEXT")},
+	}
+	migrator := newMigrator(t, db, modified)
+
+	assert.ErrorIs(t, migrator.Verify(ctx), migrate.ErrChecksumMismatch)
+	applied, err := migrator.Up(ctx)
+	assert.ErrorIs(t, err, migrate.ErrChecksumMismatch)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Modified)
+	assert.False(t, statuses[1].Modified)
+}
+
+func TestMigrator_Down_NoDownMigration(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	migrator := newMigrator(t, db, fstest.MapFS{
+		"1_create_users.up.sql": {Data: []byte("CREATE TABLE users (id TEXT PRIMARY KEY)")},
+	})
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	_, err = migrator.Down(ctx, 1)
+
+	assert.ErrorIs(t, err, migrate.ErrNoDownMigration)
+}
+
+func TestNew_SortsAndValidates(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	migrations := []migrate.Migration{
+		{Version: 2, Name: "add_email", Up: "ALTER TABLE users ADD COLUMN email TEXT"},
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users (id TEXT PRIMARY KEY)"},
+	}
+
+	migrator, err := migrate.New(db, "sqlite3", migrations)
+	require.NoError(t, err)
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, uint64(1), applied[0].Version)
+
+	_, err = migrate.New(db, "sqlite3", append(migrations, migrate.Migration{Version: 2, Name: "again"}))
+	assert.ErrorIs(t, err, migrate.ErrInvalidMigration)
+	_, err = migrate.New(db, "sqlite3", []migrate.Migration{{Version: 0, Name: "zero"}})
+	assert.ErrorIs(t, err, migrate.ErrInvalidMigration)
+}
+
+func TestMigrator_WithTable(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	migrations, err := migrate.Load(testMigrations, "migrations")
+	require.NoError(t, err)
+
+	migrator, err := migrate.New(db, "sqlite3", migrations, migrate.WithTable("schema versions"))
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM "schema versions"`).Scan(&count))
+	assert.Equal(t, 2, count)
+}
+
+func newMigrator(t *testing.T, db *sql.DB, fsys fstest.MapFS) *migrate.Migrator {
+	t.Helper()
+
+	dir := "."
+	if _, ok := fsys["migrations/1_create_users.up.sql"]; ok {
+		dir = "migrations"
+	}
+	migrations, err := migrate.Load(fsys, dir)
+	require.NoError(t, err)
+	migrator, err := migrate.New(db, "sqlite3", migrations)
+	require.NoError(t, err)
+	return migrator
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	// a single connection keeps the in-memory database alive
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
@@ -0,0 +1,366 @@
+package sqlcredotest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ConformanceEntity is the record type of the conformance suite,
+// covering the column types every driver has to round-trip.
+type ConformanceEntity struct {
+	ID        int64         `db:"id"`
+	Name      string        `db:"name"`
+	Score     float64       `db:"score"`
+	Active    bool          `db:"active"`
+	CreatedAt time.Time     `db:"created_at"`
+	Nickname  *string       `db:"nickname"`
+	Data      []byte        `db:"data"`
+	Rank      sql.NullInt64 `db:"rank"`
+}
+
+// ConformanceTable and ConformanceIDColumn are the table and ID column of ConformanceEntity.
+const (
+	ConformanceTable    = "conformance_entities"
+	ConformanceIDColumn = "id"
+)
+
+// RunConformance checks that SQLCredo behaves the same on db as on the built-in drivers:
+// it runs the contract suite and cases for column types, NULLs, sorting, transactions,
+// upserts and error mapping. It creates ContractTable and ConformanceTable,
+// which must not be used by anything else, and drops them when the test ends.
+func RunConformance(t *testing.T, db *sql.DB, driver string) {
+	t.Helper()
+
+	createTable[ContractEntity](t, db, driver, ContractTable, ContractIDColumn)
+	createTable[ConformanceEntity](t, db, driver, ConformanceTable, ConformanceIDColumn)
+
+	t.Run("contract", func(t *testing.T) {
+		RunContract(t, func(t *testing.T) Repository[ContractEntity, string] {
+			return newRepo[ContractEntity, string](t, db, driver, ContractTable, ContractIDColumn)
+		})
+	})
+
+	cases := []struct {
+		name string
+		run  func(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64])
+	}{
+		{name: "round-trip", run: conformanceRoundTrip},
+		{name: "null-values", run: conformanceNullValues},
+		{name: "sorting", run: conformanceSorting},
+		{name: "tx-commit", run: conformanceTxCommit},
+		{name: "tx-rollback", run: conformanceTxRollback},
+		{name: "tx-savepoint", run: conformanceTxSavepoint},
+		{name: "upsert", run: conformanceUpsert},
+		{name: "upsert-tenant", run: conformanceUpsertTenant},
+		{name: "error-no-rows", run: conformanceErrNoRows},
+		{name: "error-duplicate-key", run: conformanceErrDuplicateKey},
+		{name: "error-too-many-rows", run: conformanceErrTooManyRows},
+		{name: "error-invalid-filter", run: conformanceErrInvalidFilter},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.run(t, newRepo[ConformanceEntity, int64](t, db, driver, ConformanceTable, ConformanceIDColumn))
+		})
+	}
+}
+
+func createTable[T any](t *testing.T, db *sql.DB, driver, table, idColumn string) {
+	t.Helper()
+
+	schema, err := sqlcredo.GenerateSchema[T](driver, table, idColumn)
+	require.NoError(t, err)
+	_, err = db.ExecContext(context.Background(), schema)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, err := db.ExecContext(context.Background(), fmt.Sprintf("DROP TABLE IF EXISTS %q", table))
+		assert.NoError(t, err)
+	})
+}
+
+// newRepo returns a repository of the emptied table.
+func newRepo[T any, I comparable](t *testing.T, db *sql.DB, driver, table, idColumn string) sqlcredo.SQLCredo[T, I] {
+	t.Helper()
+
+	repo := sqlcredo.NewSQLCredo[T, I](db, driver, table, idColumn)
+	_, err := repo.DeleteAll(context.Background())
+	require.NoError(t, err)
+	return repo
+}
+
+// conformanceRecords returns records with whole-second UTC times,
+// which all drivers store without loss.
+func conformanceRecords() []ConformanceEntity {
+	nickname := func(s string) *string { return &s }
+	created := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	return []ConformanceEntity{
+		{ID: 1, Name: "Carl", Score: 7.5, Active: true, CreatedAt: created,
+			Nickname: nickname("C"), Data: []byte{0x00, 0xff, 0x10}, Rank: sql.NullInt64{Int64: 3, Valid: true}},
+		{ID: 2, Name: "Ann", Score: -1.25, Active: false, CreatedAt: created.Add(time.Hour)},
+		{ID: 3, Name: "Bob", Score: 10, Active: true, CreatedAt: created.Add(-24 * time.Hour),
+			Data: []byte("bob"), Rank: sql.NullInt64{Int64: 1, Valid: true}},
+		{ID: 4, Name: "Dora", Score: 0, Active: false, CreatedAt: created.Add(time.Minute),
+			Nickname: nickname("")},
+	}
+}
+
+func seedConformance(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) []ConformanceEntity {
+	t.Helper()
+
+	records := conformanceRecords()
+	for _, r := range records {
+		_, err := repo.Create(context.Background(), &r)
+		require.NoError(t, err)
+	}
+	return records
+}
+
+// inUTC drops the time zone drivers attach to loaded times.
+func inUTC(records ...ConformanceEntity) []ConformanceEntity {
+	for i := range records {
+		records[i].CreatedAt = records[i].CreatedAt.UTC()
+	}
+	return records
+}
+
+func conformanceRoundTrip(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := seedConformance(t, repo)
+	ctx := context.Background()
+
+	for _, r := range records {
+		got, err := repo.GetByID(ctx, r.ID)
+		require.NoError(t, err)
+		assert.Equal(t, r, inUTC(got)[0])
+	}
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, records, inUTC(all...))
+}
+
+func conformanceNullValues(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := seedConformance(t, repo)
+	ctx := context.Background()
+
+	unranked, err := repo.CountWhere(ctx, api.IsNull("rank"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), unranked)
+
+	// the empty string is not NULL
+	named, err := repo.GetAll(ctx, api.Where(api.IsNotNull("nickname")))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []ConformanceEntity{records[0], records[3]}, inUTC(named...))
+
+	cols := sqlcredo.Columns[ConformanceEntity]()
+	patch := sqlcredo.Patch[ConformanceEntity]{}.
+		Set(cols.Field(&cols.Model().Nickname), nil).
+		Set(cols.Field(&cols.Model().Data), nil).
+		Set(cols.Field(&cols.Model().Rank), sql.NullInt64{})
+	_, err = repo.Patch(ctx, records[0].ID, patch)
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(ctx, records[0].ID)
+	require.NoError(t, err)
+	cleared := records[0]
+	cleared.Nickname, cleared.Data, cleared.Rank = nil, nil, sql.NullInt64{}
+	assert.Equal(t, cleared, inUTC(got)[0])
+}
+
+func conformanceSorting(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := seedConformance(t, repo)
+	ctx := context.Background()
+
+	byScore, err := repo.GetPage(ctx, api.WithSortDesc("score"), api.WithSortBy("score"))
+	require.NoError(t, err)
+	assert.Equal(t, []ConformanceEntity{records[2], records[0], records[3], records[1]},
+		inUTC(byScore.Content...))
+
+	byTime, err := repo.GetPage(ctx, api.WithSortBy("created_at"), api.WithPageSize(2), api.WithPageNumber(1))
+	require.NoError(t, err)
+	assert.Equal(t, []ConformanceEntity{records[3], records[1]}, inUTC(byTime.Content...))
+	assert.Equal(t, uint64(4), byTime.Total)
+
+	byFlag, err := repo.GetPage(ctx, api.WithSortBy("active"), api.WithSortBy("name"))
+	require.NoError(t, err)
+	assert.Equal(t, []ConformanceEntity{records[1], records[3], records[2], records[0]},
+		inUTC(byFlag.Content...))
+}
+
+func conformanceTxCommit(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := conformanceRecords()
+	repo = repo.WithAmbientTx(true)
+	ctx := context.Background()
+
+	err := repo.InTx(ctx, nil, func(ctx context.Context, _ *sql.Tx) error {
+		for _, r := range records[:2] {
+			if _, err := repo.Create(ctx, &r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+}
+
+func conformanceTxRollback(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := seedConformance(t, repo)
+	repo = repo.WithAmbientTx(true)
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	err := repo.InTx(ctx, nil, func(ctx context.Context, _ *sql.Tx) error {
+		if _, err := repo.Delete(ctx, records[0].ID); err != nil {
+			return err
+		}
+		count, err := repo.Count(ctx)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, uint64(len(records)-1), count)
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	exists, err := repo.ExistsByID(ctx, records[0].ID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func conformanceTxSavepoint(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := conformanceRecords()
+	repo = repo.WithAmbientTx(true)
+	ctx := context.Background()
+
+	err := repo.InTx(ctx, nil, func(ctx context.Context, _ *sql.Tx) error {
+		if _, err := repo.Create(ctx, &records[0]); err != nil {
+			return err
+		}
+
+		// the duplicate fails the nested transaction only
+		err := repo.InTx(ctx, nil, func(ctx context.Context, _ *sql.Tx) error {
+			if _, err := repo.Create(ctx, &records[1]); err != nil {
+				return err
+			}
+			_, err := repo.Create(ctx, &records[0])
+			return err
+		})
+		assert.ErrorIs(t, err, api.ErrDuplicateKey)
+
+		_, err = repo.Create(ctx, &records[2])
+		return err
+	})
+	require.NoError(t, err)
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []ConformanceEntity{records[0], records[2]}, inUTC(all...))
+}
+
+func conformanceUpsert(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := seedConformance(t, repo)
+	ctx := context.Background()
+
+	updated := records[1]
+	updated.Score, updated.Active, updated.Data = 99.5, true, []byte{0x01}
+	_, err := repo.Upsert(ctx, &updated)
+	require.NoError(t, err)
+
+	created := ConformanceEntity{ID: 5, Name: "Eve", CreatedAt: records[0].CreatedAt}
+	_, err = repo.Upsert(ctx, &created)
+	require.NoError(t, err)
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []ConformanceEntity{records[0], updated, records[2], records[3], created},
+		inUTC(all...))
+}
+
+func conformanceUpsertTenant(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	type tenantKey struct{}
+	records := seedConformance(t, repo)
+	// records are owned by the tenant named like them
+	repo = repo.WithTenant("name", func(ctx context.Context) (any, bool) {
+		tenant, ok := ctx.Value(tenantKey{}).(string)
+		return tenant, ok
+	})
+	carl := context.WithValue(context.Background(), tenantKey{}, records[0].Name)
+	ann := context.WithValue(context.Background(), tenantKey{}, records[1].Name)
+
+	updated := records[0]
+	updated.Score = 1.5
+	_, err := repo.Upsert(carl, &updated)
+	require.NoError(t, err)
+
+	// the record of another tenant is left unchanged
+	stolen := records[0]
+	stolen.Score = 2.5
+	_, err = repo.Upsert(ann, &stolen)
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(carl, records[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, inUTC(got)[0])
+	all, err := repo.GetAll(ann)
+	require.NoError(t, err)
+	assert.Equal(t, []ConformanceEntity{records[1]}, inUTC(all...))
+}
+
+func conformanceErrNoRows(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	seedConformance(t, repo)
+
+	_, err := repo.GetByID(context.Background(), 42)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func conformanceErrDuplicateKey(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := seedConformance(t, repo)
+	ctx := context.Background()
+
+	_, err := repo.Create(ctx, &records[0])
+	assert.ErrorIs(t, err, api.ErrDuplicateKey)
+
+	moved := records[1]
+	moved.ID = records[0].ID
+	_, err = repo.Update(ctx, records[1].ID, &moved)
+	assert.ErrorIs(t, err, api.ErrDuplicateKey)
+}
+
+func conformanceErrTooManyRows(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	records := seedConformance(t, repo)
+	repo = repo.WithMaxRows(uint(len(records) - 1))
+	ctx := context.Background()
+
+	_, err := repo.GetAll(ctx)
+	assert.ErrorIs(t, err, api.ErrTooManyRows)
+
+	active, err := repo.GetAll(ctx, api.Where(api.Eq("active", true)))
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+}
+
+func conformanceErrInvalidFilter(t *testing.T, repo sqlcredo.SQLCredo[ConformanceEntity, int64]) {
+	seedConformance(t, repo)
+	ctx := context.Background()
+
+	_, err := repo.GetAll(ctx, api.Where(api.Eq("", 1)))
+	assert.ErrorIs(t, err, api.ErrInvalidFilter)
+
+	_, err = repo.CountWhere(ctx, api.Filter{Operator: "between", Column: "score"})
+	assert.ErrorIs(t, err, api.ErrInvalidFilter)
+}
@@ -0,0 +1,20 @@
+package sqlcredotest_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/pkg/sqlcredotest"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConformance_SQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "conformance.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	sqlcredotest.RunConformance(t, db, "sqlite3")
+}
@@ -0,0 +1,367 @@
+package sqlcredotest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ContractEntity is the record type of the contract suite.
+type ContractEntity struct {
+	ID    string  `db:"id"`
+	Name  string  `db:"name"`
+	Age   int64   `db:"age"`
+	Email *string `db:"email"`
+}
+
+// ContractTable and ContractIDColumn are the table and ID column the contract suite
+// expects real repositories to use. Create the table with
+// sqlcredo.GenerateSchema[ContractEntity](driver, ContractTable, ContractIDColumn).
+const (
+	ContractTable    = "contract_entities"
+	ContractIDColumn = "id"
+)
+
+// RunContract runs the contract suite against repositories created by newRepo,
+// which must return an empty repository of ContractEntity for each call.
+// Run it against both the Fake and a real SQLCredo to keep them consistent.
+func RunContract(t *testing.T, newRepo func(t *testing.T) Repository[ContractEntity, string]) {
+	t.Helper()
+
+	cases := []struct {
+		name string
+		run  func(t *testing.T, repo Repository[ContractEntity, string])
+	}{
+		{name: "create-and-get", run: contractCreateAndGet},
+		{name: "get-missing", run: contractGetMissing},
+		{name: "create-duplicate", run: contractCreateDuplicate},
+		{name: "get-all", run: contractGetAll},
+		{name: "get-by-ids", run: contractGetByIDs},
+		{name: "update", run: contractUpdate},
+		{name: "patch", run: contractPatch},
+		{name: "upsert", run: contractUpsert},
+		{name: "delete", run: contractDelete},
+		{name: "delete-all", run: contractDeleteAll},
+		{name: "filters", run: contractFilters},
+		{name: "page", run: contractPage},
+		{name: "page-sort", run: contractPageSort},
+		{name: "page-keyset", run: contractPageKeyset},
+		{name: "page-invalid", run: contractPageInvalid},
+		{name: "null-values", run: contractNullValues},
+		{name: "projection", run: contractProjection},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.run(t, newRepo(t))
+		})
+	}
+}
+
+// contractRecords returns records whose names, ages and IDs sort in different orders.
+func contractRecords() []ContractEntity {
+	email := func(s string) *string { return &s }
+	return []ContractEntity{
+		{ID: "e1", Name: "Carl", Age: 30, Email: email("carl@example.com")},
+		{ID: "e2", Name: "Ann", Age: 25, Email: nil},
+		{ID: "e3", Name: "Bob", Age: 41, Email: email("bob@example.com")},
+		{ID: "e4", Name: "Dora", Age: 19, Email: nil},
+		{ID: "e5", Name: "Abe", Age: 35, Email: email("abe@example.org")},
+	}
+}
+
+func seed(t *testing.T, repo Repository[ContractEntity, string]) []ContractEntity {
+	t.Helper()
+
+	records := contractRecords()
+	for _, r := range records {
+		_, err := repo.Create(context.Background(), &r)
+		require.NoError(t, err)
+	}
+	return records
+}
+
+func rowsAffected(t *testing.T, res sql.Result) int64 {
+	t.Helper()
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	return n
+}
+
+func contractCreateAndGet(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+
+	got, err := repo.GetByID(context.Background(), "e3")
+
+	require.NoError(t, err)
+	assert.Equal(t, records[2], got)
+}
+
+func contractGetMissing(t *testing.T, repo Repository[ContractEntity, string]) {
+	seed(t, repo)
+
+	_, err := repo.GetByID(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func contractCreateDuplicate(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+
+	_, err := repo.Create(context.Background(), &records[0])
+
+	assert.ErrorIs(t, err, api.ErrDuplicateKey)
+}
+
+func contractGetAll(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+	ctx := context.Background()
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, records, all)
+
+	adults, err := repo.GetAll(ctx, api.Where(api.Gte("age", 30)))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []ContractEntity{records[0], records[2], records[4]}, adults)
+}
+
+func contractGetByIDs(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+
+	got, err := repo.GetByIDs(context.Background(), []string{"e4", "missing", "e1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ContractEntity{records[0], records[3]}, got)
+}
+
+func contractUpdate(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+	ctx := context.Background()
+
+	updated := records[1]
+	updated.Name = "Anna"
+	res, err := repo.Update(ctx, updated.ID, &updated)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rowsAffected(t, res))
+
+	got, err := repo.GetByID(ctx, updated.ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, got)
+
+	res, err = repo.Update(ctx, "missing", &ContractEntity{ID: "missing", Name: "Nobody"})
+	require.NoError(t, err)
+	assert.Zero(t, rowsAffected(t, res))
+	exists, err := repo.ExistsByID(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func contractPatch(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+	ctx := context.Background()
+	cols := sqlcredo.Columns[ContractEntity]()
+	name, email := cols.Field(&cols.Model().Name), cols.Field(&cols.Model().Email)
+
+	res, err := repo.Patch(ctx, "e1", sqlcredo.Patch[ContractEntity]{}.Set(name, "Carlos").Set(email, nil))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rowsAffected(t, res))
+
+	got, err := repo.GetByID(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, ContractEntity{ID: "e1", Name: "Carlos", Age: records[0].Age}, got)
+
+	res, err = repo.Patch(ctx, "missing", sqlcredo.Patch[ContractEntity]{}.Set(name, "Nobody"))
+	require.NoError(t, err)
+	assert.Zero(t, rowsAffected(t, res))
+}
+
+func contractUpsert(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+	ctx := context.Background()
+
+	updated := records[0]
+	updated.Name, updated.Email = "Carla", nil
+	_, err := repo.Upsert(ctx, &updated)
+	require.NoError(t, err)
+
+	created := ContractEntity{ID: "e9", Name: "Zoe", Age: 19}
+	_, err = repo.Upsert(ctx, &created)
+	require.NoError(t, err)
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, append([]ContractEntity{updated, created}, records[1:]...), all)
+}
+
+func contractDelete(t *testing.T, repo Repository[ContractEntity, string]) {
+	seed(t, repo)
+	ctx := context.Background()
+
+	res, err := repo.Delete(ctx, "e2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rowsAffected(t, res))
+
+	exists, err := repo.ExistsByID(ctx, "e2")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	res, err = repo.Delete(ctx, "e2")
+	require.NoError(t, err)
+	assert.Zero(t, rowsAffected(t, res))
+}
+
+func contractDeleteAll(t *testing.T, repo Repository[ContractEntity, string]) {
+	seed(t, repo)
+	ctx := context.Background()
+
+	_, err := repo.DeleteAll(ctx)
+	require.NoError(t, err)
+
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func contractFilters(t *testing.T, repo Repository[ContractEntity, string]) {
+	seed(t, repo)
+	ctx := context.Background()
+
+	tests := []struct {
+		filter api.Filter
+		count  uint64
+	}{
+		{filter: api.Filter{}, count: 5},
+		{filter: api.Eq("name", "Bob"), count: 1},
+		{filter: api.Ne("name", "Bob"), count: 4},
+		{filter: api.Gt("age", 30), count: 2},
+		{filter: api.Lte("age", 25), count: 2},
+		{filter: api.In("id", "e1", "e3", "missing"), count: 2},
+		{filter: api.In("id"), count: 0},
+		{filter: api.NotIn("id", "e1", "e3"), count: 3},
+		{filter: api.Like("email", "%@example.com"), count: 2},
+		{filter: api.IsNull("email"), count: 2},
+		{filter: api.IsNotNull("email"), count: 3},
+		{filter: api.Ne("email", "bob@example.com"), count: 2}, // NULL is not unequal
+		{filter: api.And(api.Gte("age", 25), api.Lt("age", 40)), count: 3},
+		{filter: api.Or(api.Eq("name", "Ann"), api.Eq("name", "Dora")), count: 2},
+		{filter: api.Or(api.Filter{}, api.Eq("name", "Ann")), count: 5},
+		{filter: api.Or(), count: 0},
+		{filter: api.And(api.Filter{}, api.Eq("name", "Ann")), count: 1},
+		{filter: api.Not(api.Eq("name", "Ann")), count: 4},
+	}
+
+	for _, tt := range tests {
+		count, err := repo.CountWhere(ctx, tt.filter)
+		require.NoError(t, err)
+		assert.Equal(t, tt.count, count, "filter %+v", tt.filter)
+
+		exists, err := repo.Exists(ctx, tt.filter)
+		require.NoError(t, err)
+		assert.Equal(t, tt.count > 0, exists, "filter %+v", tt.filter)
+	}
+}
+
+func contractPage(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+	ctx := context.Background()
+
+	page, err := repo.GetPage(ctx, api.WithPageNumber(1), api.WithPageSize(2))
+	require.NoError(t, err)
+	assert.Equal(t, api.Page[ContractEntity]{
+		Number:     1,
+		Size:       2,
+		Total:      5,
+		TotalPages: 3,
+		Content:    []ContractEntity{records[2], records[3]},
+	}, page)
+
+	page, err = repo.GetPage(ctx, api.WithPageNumber(2), api.WithPageSize(2),
+		api.WithFilter(api.IsNotNull("email")))
+	require.NoError(t, err)
+	assert.Equal(t, api.Page[ContractEntity]{}, page)
+
+	page, err = repo.GetPage(ctx, api.WithPageNumber(10))
+	require.NoError(t, err)
+	assert.Equal(t, api.Page[ContractEntity]{}, page)
+}
+
+func contractPageSort(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+	ctx := context.Background()
+
+	page, err := repo.GetPage(ctx, api.WithSortBy("name"), api.WithPageSize(3))
+	require.NoError(t, err)
+	assert.Equal(t, []ContractEntity{records[4], records[1], records[2]}, page.Content)
+
+	page, err = repo.GetPage(ctx, api.WithSortBy("age"), api.WithSortDesc("age"), api.WithPageSize(2))
+	require.NoError(t, err)
+	assert.Equal(t, []ContractEntity{records[2], records[4]}, page.Content)
+	assert.Equal(t, uint(3), page.TotalPages)
+}
+
+func contractPageKeyset(t *testing.T, repo Repository[ContractEntity, string]) {
+	records := seed(t, repo)
+	ctx := context.Background()
+
+	page, err := repo.GetPage(ctx, api.WithSortBy("age"), api.WithAfter(int64(25)), api.WithPageSize(2))
+	require.NoError(t, err)
+	assert.Equal(t, []ContractEntity{records[0], records[4]}, page.Content)
+
+	page, err = repo.GetPage(ctx, api.WithSortBy("name"), api.WithSortBy("id"),
+		api.WithSortDesc("name"), api.WithAfter("Bob", "e3"))
+	require.NoError(t, err)
+	assert.Equal(t, []ContractEntity{records[1], records[4]}, page.Content)
+}
+
+func contractPageInvalid(t *testing.T, repo Repository[ContractEntity, string]) {
+	ctx := context.Background()
+
+	_, err := repo.GetPage(ctx, api.WithPageSize(0))
+	assert.ErrorIs(t, err, api.ErrInvalidPageSize)
+
+	_, err = repo.GetPage(ctx, api.WithAfter("a", "b"))
+	assert.ErrorIs(t, err, api.ErrInvalidCursor)
+}
+
+func contractNullValues(t *testing.T, repo Repository[ContractEntity, string]) {
+	ctx := context.Background()
+	email := "eve@example.com"
+
+	_, err := repo.Create(ctx, &ContractEntity{ID: "n1", Name: "Nil"})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &ContractEntity{ID: "n2", Name: "Eve", Email: &email})
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(ctx, "n1")
+	require.NoError(t, err)
+	assert.Nil(t, got.Email)
+	got, err = repo.GetByID(ctx, "n2")
+	require.NoError(t, err)
+	require.NotNil(t, got.Email)
+	assert.Equal(t, email, *got.Email)
+
+	count, err := repo.CountWhere(ctx, api.IsNull("email"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+}
+
+func contractProjection(t *testing.T, repo Repository[ContractEntity, string]) {
+	seed(t, repo)
+	ctx := context.Background()
+
+	got, err := repo.GetByID(ctx, "e1", api.Select("id", "name"))
+	require.NoError(t, err)
+	assert.Equal(t, ContractEntity{ID: "e1", Name: "Carl"}, got)
+
+	all, err := repo.GetAll(ctx, api.Select("id"), api.Where(api.Eq("name", "Ann")))
+	require.NoError(t, err)
+	assert.Equal(t, []ContractEntity{{ID: "e2"}}, all)
+}
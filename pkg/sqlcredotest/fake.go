@@ -0,0 +1,548 @@
+// Package sqlcredotest provides test helpers for code built on SQLCredo:
+// an in-memory fake repository and a contract suite that both the fake and
+// real repositories pass.
+package sqlcredotest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/internal/structmap"
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// Repository is the part of SQLCredo the fake implements.
+type Repository[T any, I comparable] interface {
+	api.CRUD[T, I]
+	api.PageResolver[T]
+
+	// Patch sets the columns of the patch on the record identified by id.
+	Patch(ctx context.Context, id I, patch sqlcredo.Patch[T]) (sql.Result, error)
+}
+
+var _ Repository[any, string] = sqlcredo.SQLCredo[any, string](nil)
+
+// Fake is an in-memory Repository for unit tests. It follows the semantics of
+// SQLCredo on a real database: missing records fail with sql.ErrNoRows,
+// duplicate IDs are rejected, pages are sorted by the ID column by default,
+// and filters compare like SQL, so NULL matches only IsNull.
+// Records are deep-copied on the way in and out, including pointer, slice and
+// map fields. Fake is safe for concurrent use.
+type Fake[T any, I comparable] struct {
+	mu       sync.RWMutex
+	records  []T // in insertion order
+	idColumn string
+	idField  structmap.Field
+	fields   []structmap.Field
+}
+
+var _ Repository[any, string] = &Fake[any, string]{}
+
+// NewFake creates an empty Fake for records of T identified by idColumn.
+// It panics if no field of T maps idColumn.
+func NewFake[T any, I comparable](idColumn string) *Fake[T, I] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	idField, ok := structmap.ByColumn(t, idColumn)
+	if !ok {
+		panic(fmt.Sprintf("sqlcredotest: no field of %s maps id column %q", t, idColumn))
+	}
+	return &Fake[T, I]{
+		idColumn: idColumn,
+		idField:  idField,
+		fields:   structmap.Fields(t),
+	}
+}
+
+// GetAll returns all records in insertion order.
+func (f *Fake[T, I]) GetAll(_ context.Context, opts ...api.QueryOpt) ([]T, error) {
+	var params api.QueryParams
+	for _, o := range opts {
+		o(&params)
+	}
+	if len(params.Preload) > 0 {
+		return nil, fmt.Errorf("%w: %s", api.ErrUnknownRelation, params.Preload[0])
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	records, err := f.filter(params.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load records: %w", err)
+	}
+	return f.project(records, params.Columns)
+}
+
+func (f *Fake[T, I]) GetByID(_ context.Context, id I, opts ...api.QueryOpt) (T, error) {
+	var params api.QueryParams
+	for _, o := range opts {
+		o(&params)
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var zero T
+	i := f.indexOf(id)
+	if i < 0 {
+		return zero, fmt.Errorf("unable to select record: %w", sql.ErrNoRows)
+	}
+	ok, err := f.match(f.records[i], params.Filter)
+	if err != nil {
+		return zero, fmt.Errorf("unable to select record: %w", err)
+	}
+	if !ok {
+		return zero, fmt.Errorf("unable to select record: %w", sql.ErrNoRows)
+	}
+
+	res, err := f.project([]T{f.records[i]}, params.Columns)
+	if err != nil {
+		return zero, err
+	}
+	return res[0], nil
+}
+
+// GetByIDs returns the records with the given IDs sorted by ID; missing IDs are skipped.
+func (f *Fake[T, I]) GetByIDs(ctx context.Context, ids []I, opts ...api.QueryOpt) ([]T, error) {
+	var params api.QueryParams
+	for _, o := range opts {
+		o(&params)
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var records []T
+	for _, r := range f.records {
+		if !slices.Contains(ids, f.id(r)) {
+			continue
+		}
+		ok, err := f.match(r, params.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to select records: %w", err)
+		}
+		if ok {
+			records = append(records, r)
+		}
+	}
+	if err := f.sort(records, []string{f.idColumn}, false); err != nil {
+		return nil, fmt.Errorf("unable to select records: %w", err)
+	}
+	return f.project(records, params.Columns)
+}
+
+func (f *Fake[T, I]) ExistsByID(ctx context.Context, id I) (bool, error) {
+	return f.Exists(ctx, api.Eq(f.idColumn, id))
+}
+
+func (f *Fake[T, I]) Exists(_ context.Context, filter api.Filter) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	records, err := f.filter(filter)
+	if err != nil {
+		return false, fmt.Errorf("unable to check existence: %w", err)
+	}
+	return len(records) > 0, nil
+}
+
+// Create inserts a copy of e, failing if a record with its ID exists.
+func (f *Fake[T, I]) Create(_ context.Context, e *T) (sql.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.id(*e)
+	if f.indexOf(id) >= 0 {
+		return nil, fmt.Errorf("unable to create record: %w: %s %v", api.ErrDuplicateKey, f.idColumn, id)
+	}
+	f.records = append(f.records, deepCopy(*e))
+	return result(1), nil
+}
+
+func (f *Fake[T, I]) Upsert(_ context.Context, e *T) (sql.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if i := f.indexOf(f.id(*e)); i >= 0 {
+		f.records[i] = deepCopy(*e)
+	} else {
+		f.records = append(f.records, deepCopy(*e))
+	}
+	return result(1), nil
+}
+
+func (f *Fake[T, I]) DeleteAll(context.Context) (sql.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := len(f.records)
+	f.records = nil
+	return result(n), nil
+}
+
+func (f *Fake[T, I]) Delete(_ context.Context, id I) (sql.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := f.indexOf(id)
+	if i < 0 {
+		return result(0), nil
+	}
+	f.records = slices.Delete(f.records, i, i+1)
+	return result(1), nil
+}
+
+// Update replaces the record identified by id with a copy of e; it affects
+// no rows if the record doesn't exist.
+func (f *Fake[T, I]) Update(_ context.Context, id I, e *T) (sql.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := f.indexOf(id)
+	if i < 0 {
+		return result(0), nil
+	}
+	if newID := f.id(*e); newID != id && f.indexOf(newID) >= 0 {
+		return nil, fmt.Errorf("unable to update record: %w: %s %v", api.ErrDuplicateKey, f.idColumn, newID)
+	}
+	f.records[i] = deepCopy(*e)
+	return result(1), nil
+}
+
+func (f *Fake[T, I]) Patch(_ context.Context, id I, patch sqlcredo.Patch[T]) (sql.Result, error) {
+	values := patch.Values()
+	if len(values) == 0 {
+		return nil, fmt.Errorf("unable to update columns: no values")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := f.indexOf(id)
+	if i < 0 {
+		return result(0), nil
+	}
+
+	record := f.records[i]
+	v := reflect.ValueOf(&record).Elem()
+	for column, value := range values {
+		field, err := f.field(v, column)
+		if err != nil {
+			return nil, fmt.Errorf("unable to update columns: %w", err)
+		}
+		if err := assign(field, value); err != nil {
+			return nil, fmt.Errorf("unable to update column %q: %w", column, err)
+		}
+	}
+	if newID := f.id(record); newID != id && f.indexOf(newID) >= 0 {
+		return nil, fmt.Errorf("unable to update columns: %w: %s %v", api.ErrDuplicateKey, f.idColumn, newID)
+	}
+	f.records[i] = deepCopy(record)
+	return result(1), nil
+}
+
+func (f *Fake[T, I]) GetPage(_ context.Context, opts ...api.PageOpt) (api.Page[T], error) {
+	params := api.PageParams{PageSize: 10}
+	for _, o := range opts {
+		o(&params)
+	}
+	if err := params.Validate(); err != nil {
+		return api.Page[T]{}, fmt.Errorf("unable to create page request: invalid page params: %w", err)
+	}
+	if params.SortBy == nil {
+		params.SortBy = []string{f.idColumn}
+	}
+	if len(params.After) > 0 && len(params.After) != len(params.SortBy) {
+		return api.Page[T]{}, fmt.Errorf("unable to create page request: cursor has %d values for %d sort columns: %w",
+			len(params.After), len(params.SortBy), api.ErrInvalidCursor)
+	}
+	if len(params.Preload) > 0 {
+		return api.Page[T]{}, fmt.Errorf("%w: %s", api.ErrUnknownRelation, params.Preload[0])
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	matching, err := f.filter(params.Filter)
+	if err != nil {
+		return api.Page[T]{}, fmt.Errorf("unable to get page items: %w", err)
+	}
+	if err := f.sort(matching, params.SortBy, params.SortDesc); err != nil {
+		return api.Page[T]{}, fmt.Errorf("unable to get page items: %w", err)
+	}
+
+	records := matching
+	if len(params.After) > 0 {
+		records, err = f.after(records, params)
+		if err != nil {
+			return api.Page[T]{}, fmt.Errorf("unable to get page items: %w", err)
+		}
+	} else {
+		records = records[min(uint(len(records)), params.PageNumber*params.PageSize):]
+	}
+	records = records[:min(uint(len(records)), params.PageSize)]
+
+	content, err := f.project(records, params.Columns)
+	if err != nil || len(content) == 0 {
+		return api.Page[T]{}, err
+	}
+	return api.Page[T]{
+		Number:     params.PageNumber,
+		Size:       uint(len(content)),
+		Total:      uint64(len(matching)),
+		TotalPages: uint(math.Ceil(float64(len(matching)) / float64(params.PageSize))),
+		Content:    content,
+	}, nil
+}
+
+func (f *Fake[T, I]) Count(ctx context.Context) (uint64, error) {
+	return f.CountWhere(ctx, api.Filter{})
+}
+
+func (f *Fake[T, I]) CountWhere(_ context.Context, filter api.Filter) (uint64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	records, err := f.filter(filter)
+	if err != nil {
+		return 0, fmt.Errorf("unable to count records: %w", err)
+	}
+	return uint64(len(records)), nil
+}
+
+func (f *Fake[T, I]) id(e T) I {
+	v := structmap.FieldByIndex(reflect.ValueOf(&e).Elem(), f.idField.Index)
+	var id I
+	reflect.ValueOf(&id).Elem().Set(v.Convert(reflect.TypeOf(id)))
+	return id
+}
+
+func (f *Fake[T, I]) indexOf(id I) int {
+	return slices.IndexFunc(f.records, func(e T) bool { return f.id(e) == id })
+}
+
+func (f *Fake[T, I]) filter(filter api.Filter) ([]T, error) {
+	var res []T
+	for _, r := range f.records {
+		ok, err := f.match(r, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+func (f *Fake[T, I]) match(e T, filter api.Filter) (bool, error) {
+	return match(filter, func(column string) (any, error) { return f.value(e, column) })
+}
+
+// value returns the value of column in e; nil embedded pointers read as NULL.
+func (f *Fake[T, I]) value(e T, column string) (any, error) {
+	sf, ok := structmap.ByColumn(reflect.TypeOf(e), column)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", api.ErrUnknownColumn, column)
+	}
+	v, err := reflect.ValueOf(e).FieldByIndexErr(sf.Index)
+	if err != nil {
+		return nil, nil
+	}
+	return v.Interface(), nil
+}
+
+func (f *Fake[T, I]) field(v reflect.Value, column string) (reflect.Value, error) {
+	sf, ok := structmap.ByColumn(v.Type(), column)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w: %q", api.ErrUnknownColumn, column)
+	}
+	return structmap.FieldByIndex(v, sf.Index), nil
+}
+
+// sort sorts records stably by the columns; like SQL, ties keep no particular order.
+func (f *Fake[T, I]) sort(records []T, columns []string, desc bool) error {
+	var err error
+	sort.SliceStable(records, func(i, j int) bool {
+		c, cmpErr := f.compareColumns(records[i], records[j], columns)
+		if cmpErr != nil {
+			err = cmpErr
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+	return err
+}
+
+func (f *Fake[T, I]) compareColumns(a, b T, columns []string) (int, error) {
+	for _, column := range columns {
+		va, err := f.value(a, column)
+		if err != nil {
+			return 0, err
+		}
+		vb, err := f.value(b, column)
+		if err != nil {
+			return 0, err
+		}
+		c, err := compareNullsFirst(va, vb)
+		if err != nil || c != 0 {
+			return c, err
+		}
+	}
+	return 0, nil
+}
+
+// after returns the records following the keyset cursor in the sort order.
+func (f *Fake[T, I]) after(records []T, params api.PageParams) ([]T, error) {
+	for i, r := range records {
+		for j, column := range params.SortBy {
+			v, err := f.value(r, column)
+			if err != nil {
+				return nil, err
+			}
+			c, err := compareNullsFirst(v, params.After[j])
+			if err != nil {
+				return nil, err
+			}
+			if params.SortDesc {
+				c = -c
+			}
+			if c > 0 {
+				return records[i:], nil
+			}
+			if c < 0 {
+				break
+			}
+		}
+	}
+	return nil, nil
+}
+
+// project returns copies of records with only the columns loaded, all if columns is empty.
+func (f *Fake[T, I]) project(records []T, columns []string) ([]T, error) {
+	if len(columns) == 0 {
+		res := make([]T, len(records))
+		for i, r := range records {
+			res[i] = deepCopy(r)
+		}
+		return res, nil
+	}
+
+	res := make([]T, len(records))
+	for i, r := range records {
+		src := reflect.ValueOf(r)
+		dst := reflect.ValueOf(&res[i]).Elem()
+		for _, column := range columns {
+			sf, ok := structmap.ByColumn(src.Type(), column)
+			if !ok {
+				return nil, fmt.Errorf("unable to load records: %w: %q", api.ErrUnknownColumn, column)
+			}
+			v, err := src.FieldByIndexErr(sf.Index)
+			if err != nil {
+				continue
+			}
+			structmap.FieldByIndex(dst, sf.Index).Set(cloneValue(v))
+		}
+	}
+	return res, nil
+}
+
+func compareNullsFirst(a, b any) (int, error) {
+	_, aNull := normalize(a)
+	_, bNull := normalize(b)
+	switch {
+	case aNull && bNull:
+		return 0, nil
+	case aNull:
+		return -1, nil
+	case bNull:
+		return 1, nil
+	}
+	c, _, err := compare(a, b)
+	return c, err
+}
+
+// assign sets field to value, converting between types of the same kind
+// and wrapping values of pointer fields.
+func assign(field reflect.Value, value any) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if field.Kind() == reflect.Pointer && v.Kind() != reflect.Pointer {
+		ptr := reflect.New(field.Type().Elem())
+		if err := assign(ptr.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	if !v.Type().ConvertibleTo(field.Type()) {
+		return fmt.Errorf("%T is not convertible to %s", value, field.Type())
+	}
+	field.Set(v.Convert(field.Type()))
+	return nil
+}
+
+// deepCopy returns a copy of r that shares no memory with it.
+func deepCopy[T any](r T) T {
+	return cloneValue(reflect.ValueOf(&r).Elem()).Interface().(T)
+}
+
+// cloneValue copies the pointers, slices and maps reachable from v through exported fields;
+// unexported fields, e.g. the location of a time.Time, are shared.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(cloneValue(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				c.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+		return c
+	}
+	return v
+}
+
+type result int64
+
+func (r result) LastInsertId() (int64, error) { return 0, nil }
+func (r result) RowsAffected() (int64, error) { return int64(r), nil }
@@ -0,0 +1,88 @@
+package sqlcredotest_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+	"github.com/Klojer/sqlcredo/pkg/sqlcredotest"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake_Contract(t *testing.T) {
+	sqlcredotest.RunContract(t, func(t *testing.T) sqlcredotest.Repository[sqlcredotest.ContractEntity, string] {
+		return sqlcredotest.NewFake[sqlcredotest.ContractEntity, string](sqlcredotest.ContractIDColumn)
+	})
+}
+
+func TestSQLCredo_Contract(t *testing.T) {
+	sqlcredotest.RunContract(t, func(t *testing.T) sqlcredotest.Repository[sqlcredotest.ContractEntity, string] {
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "contract.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		repo := sqlcredo.NewSQLCredo[sqlcredotest.ContractEntity, string](db, "sqlite3",
+			sqlcredotest.ContractTable, sqlcredotest.ContractIDColumn)
+		schema, err := sqlcredo.GenerateSchema[sqlcredotest.ContractEntity]("sqlite3",
+			sqlcredotest.ContractTable, sqlcredotest.ContractIDColumn)
+		require.NoError(t, err)
+		_, err = repo.InitSchema(context.Background(), schema)
+		require.NoError(t, err)
+		return repo
+	})
+}
+
+type identity string
+
+type fakeEntity struct {
+	ID   identity `db:"id"`
+	Tags *string  `db:"tags"`
+}
+
+func TestFake_NamedIDType(t *testing.T) {
+	ctx := context.Background()
+	fake := sqlcredotest.NewFake[fakeEntity, identity]("id")
+
+	_, err := fake.Create(ctx, &fakeEntity{ID: "a"})
+	require.NoError(t, err)
+
+	got, err := fake.GetByID(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, fakeEntity{ID: "a"}, got)
+
+	_, err = fake.GetAll(ctx, api.Where(api.Eq("missing", 1)))
+	assert.ErrorIs(t, err, api.ErrUnknownColumn)
+}
+
+func TestNewFake_UnknownIDColumn(t *testing.T) {
+	assert.Panics(t, func() { sqlcredotest.NewFake[fakeEntity, identity]("uuid") })
+}
+
+func TestFake_CopiesReferenceFields(t *testing.T) {
+	ctx := context.Background()
+	fake := sqlcredotest.NewFake[sqlcredotest.ContractEntity, string](sqlcredotest.ContractIDColumn)
+	email := "ann@example.com"
+	record := sqlcredotest.ContractEntity{ID: "e1", Name: "Ann", Email: &email}
+
+	_, err := fake.Create(ctx, &record)
+	require.NoError(t, err)
+	email = "changed@example.com"
+
+	got, err := fake.GetByID(ctx, "e1")
+	require.NoError(t, err)
+	require.NotNil(t, got.Email)
+	assert.Equal(t, "ann@example.com", *got.Email)
+
+	*got.Email = "changed@example.com"
+	cols := sqlcredo.Columns[sqlcredotest.ContractEntity]()
+	projected, err := fake.GetAll(ctx, sqlcredo.Select(cols.Field(&cols.Model().Email)))
+	require.NoError(t, err)
+	require.Len(t, projected, 1)
+	assert.Equal(t, "ann@example.com", *projected[0].Email)
+}
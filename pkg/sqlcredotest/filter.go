@@ -0,0 +1,233 @@
+package sqlcredotest
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// match evaluates filter against a record with SQL semantics: comparisons
+// involving NULL don't match, and the zero filter matches everything.
+func match(filter api.Filter, value func(column string) (any, error)) (bool, error) {
+	switch filter.Operator {
+	case "":
+		return true, nil
+	case api.OpAnd, api.OpOr:
+		and := filter.Operator == api.OpAnd
+		for _, f := range filter.Filters {
+			if f.IsZero() {
+				if !and {
+					// the zero filter matches all records
+					return true, nil
+				}
+				continue
+			}
+			ok, err := match(f, value)
+			if err != nil {
+				return false, err
+			}
+			if ok != and {
+				return ok, nil
+			}
+		}
+		// an empty Or matches no records
+		return and, nil
+	case api.OpNot:
+		if len(filter.Filters) != 1 {
+			return false, fmt.Errorf("%w: not requires exactly one filter", api.ErrInvalidFilter)
+		}
+		ok, err := match(filter.Filters[0], value)
+		return !ok, err
+	}
+
+	if filter.Column == "" {
+		return false, fmt.Errorf("%w: missing column for %s", api.ErrInvalidFilter, filter.Operator)
+	}
+	v, err := value(filter.Column)
+	if err != nil {
+		return false, err
+	}
+	v, isNull := normalize(v)
+
+	switch filter.Operator {
+	case api.OpIsNull:
+		return isNull, nil
+	case api.OpIsNotNull:
+		return !isNull, nil
+	}
+	if isNull {
+		return false, nil
+	}
+
+	switch filter.Operator {
+	case api.OpIn, api.OpNotIn:
+		values, ok := filter.Value.([]any)
+		if !ok {
+			return false, fmt.Errorf("%w: %s requires a list of values", api.ErrInvalidFilter, filter.Operator)
+		}
+		found := false
+		for _, operand := range values {
+			c, null, err := compare(v, operand)
+			if err != nil {
+				return false, err
+			}
+			found = found || (!null && c == 0)
+		}
+		return found == (filter.Operator == api.OpIn), nil
+	case api.OpLike:
+		s, ok := v.(string)
+		pattern, okPattern := filter.Value.(string)
+		if !ok || !okPattern {
+			return false, fmt.Errorf("%w: like requires text", api.ErrInvalidFilter)
+		}
+		return likeRegexp(pattern).MatchString(s), nil
+	}
+
+	c, null, err := compare(v, filter.Value)
+	if err != nil || null {
+		return false, err
+	}
+	switch filter.Operator {
+	case api.OpEq:
+		return c == 0, nil
+	case api.OpNe:
+		return c != 0, nil
+	case api.OpGt:
+		return c > 0, nil
+	case api.OpGte:
+		return c >= 0, nil
+	case api.OpLt:
+		return c < 0, nil
+	case api.OpLte:
+		return c <= 0, nil
+	}
+	return false, fmt.Errorf("%w: unknown operator %q", api.ErrInvalidFilter, filter.Operator)
+}
+
+// compare compares two column values; null reports whether either of them is NULL.
+func compare(a, b any) (c int, null bool, err error) {
+	a, aNull := normalize(a)
+	b, bNull := normalize(b)
+	if aNull || bNull {
+		return 0, true, nil
+	}
+
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return cmp(x, y), false, nil
+		case float64:
+			return cmp(float64(x), y), false, nil
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return cmp(x, float64(y)), false, nil
+		case float64:
+			return cmp(x, y), false, nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), false, nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return cmp(boolInt(x), boolInt(y)), false, nil
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), false, nil
+		}
+	}
+	return 0, false, fmt.Errorf("unable to compare %T with %T", a, b)
+}
+
+type ordered interface {
+	~int64 | ~float64
+}
+
+func cmp[N ordered](a, b N) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// normalize converts a value to int64, float64, string, bool or time.Time,
+// dereferencing pointers and driver.Valuer types; isNull reports NULL values.
+func normalize(v any) (res any, isNull bool) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil, true
+		}
+		if value, err := valuer.Value(); err == nil {
+			v = value
+		}
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, true
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, true
+	}
+
+	if t, ok := rv.Interface().(time.Time); ok {
+		return t, false
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), false
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), false
+	case reflect.String:
+		return rv.String(), false
+	case reflect.Bool:
+		return rv.Bool(), false
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes()), false
+		}
+	}
+	return rv.Interface(), false
+}
+
+// likeRegexp converts a LIKE pattern to an anchored regular expression.
+func likeRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
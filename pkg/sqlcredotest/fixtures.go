@@ -0,0 +1,188 @@
+package sqlcredotest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+
+	"github.com/Klojer/sqlcredo/internal/structmap"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures loads records from YAML or JSON files into tables through repositories.
+// A fixture file maps table names to lists of records keyed by column:
+//
+//	users:
+//	  - id: u1
+//	    name: John
+//	orders:
+//	  - id: o1
+//	    user_id: u1
+type Fixtures struct {
+	tables map[string]*fixtureTable
+	order  []string
+}
+
+type fixtureTable struct {
+	dependsOn []string
+	insert    func(ctx context.Context, record map[string]yaml.Node) error
+}
+
+// NewFixtures returns a loader without tables; add them with RegisterFixture.
+func NewFixtures() *Fixtures {
+	return &Fixtures{tables: make(map[string]*fixtureTable)}
+}
+
+// RegisterFixture makes the records of table load through repo. Tables that
+// table references by foreign keys are listed in dependsOn and loaded first.
+func RegisterFixture[T any, I comparable](f *Fixtures, table string, repo api.CRUD[T, I], dependsOn ...string) {
+	if _, ok := f.tables[table]; !ok {
+		f.order = append(f.order, table)
+	}
+	f.tables[table] = &fixtureTable{
+		dependsOn: dependsOn,
+		insert: func(ctx context.Context, record map[string]yaml.Node) error {
+			var e T
+			if err := decodeRecord(reflect.ValueOf(&e).Elem(), record); err != nil {
+				return err
+			}
+			_, err := repo.Create(ctx, &e)
+			return err
+		},
+	}
+}
+
+// Load inserts the records of the files of fsys matching patterns (see fs.Glob),
+// parent tables before the tables referencing them. Records of a table are
+// inserted in file order. With a context of RollbackTx and repositories with
+// ambient transactions, the records are gone when the test ends.
+func (f *Fixtures) Load(ctx context.Context, fsys fs.FS, patterns ...string) error {
+	records := make(map[string][]map[string]yaml.Node)
+	for _, pattern := range patterns {
+		names, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return fmt.Errorf("unable to find fixtures %q: %w", pattern, err)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("unable to find fixtures %q: no matching files", pattern)
+		}
+		for _, name := range names {
+			if err := f.read(fsys, name, records); err != nil {
+				return err
+			}
+		}
+	}
+
+	order, err := f.loadOrder()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range order {
+		for i, record := range records[table] {
+			if err := f.tables[table].insert(ctx, record); err != nil {
+				return fmt.Errorf("unable to load fixture %s[%d]: %w", table, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Fixtures) read(fsys fs.FS, name string, records map[string][]map[string]yaml.Node) error {
+	switch path.Ext(name) {
+	case ".yaml", ".yml", ".json":
+		// JSON is a subset of YAML
+	default:
+		return fmt.Errorf("unable to read fixtures %s: not a YAML or JSON file", name)
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("unable to read fixtures: %w", err)
+	}
+
+	var file map[string][]map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("unable to parse fixtures %s: %w", name, err)
+	}
+	for table, rows := range file {
+		if _, ok := f.tables[table]; !ok {
+			return fmt.Errorf("unable to read fixtures %s: table %q is not registered", name, table)
+		}
+		records[table] = append(records[table], rows...)
+	}
+	return nil
+}
+
+// loadOrder sorts the registered tables so that dependencies come first.
+func (f *Fixtures) loadOrder() ([]string, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(f.tables))
+	order := make([]string, 0, len(f.tables))
+
+	var visit func(table string, path []string) error
+	visit = func(table string, path []string) error {
+		switch state[table] {
+		case visiting:
+			return fmt.Errorf("unable to order fixtures: dependency cycle %v", append(path, table))
+		case done:
+			return nil
+		}
+
+		t, ok := f.tables[table]
+		if !ok {
+			return fmt.Errorf("unable to order fixtures: %s depends on unregistered table %q", path[len(path)-1], table)
+		}
+		state[table] = visiting
+		for _, dep := range t.dependsOn {
+			if err := visit(dep, append(path, table)); err != nil {
+				return err
+			}
+		}
+		state[table] = done
+		order = append(order, table)
+		return nil
+	}
+
+	for _, table := range f.order {
+		if err := visit(table, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// decodeRecord sets the fields of v mapped to the columns of record.
+func decodeRecord(v reflect.Value, record map[string]yaml.Node) error {
+	for column, node := range record {
+		f, ok := structmap.ByColumn(v.Type(), column)
+		if !ok {
+			return fmt.Errorf("%w: %q in %s", api.ErrUnknownColumn, column, v.Type())
+		}
+		if err := decodeValue(structmap.FieldByIndex(v, f.Index), &node); err != nil {
+			return fmt.Errorf("unable to decode column %q: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// decodeValue decodes node into field; sql.Scanner fields, e.g. sql.NullInt64,
+// scan the plain value like rows do.
+func decodeValue(field reflect.Value, node *yaml.Node) error {
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		return scanner.Scan(value)
+	}
+	return node.Decode(field.Addr().Interface())
+}
@@ -0,0 +1,164 @@
+package sqlcredotest_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+	"github.com/Klojer/sqlcredo/pkg/sqlcredotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtureSchema = `
+CREATE TABLE users (
+    id TEXT NOT NULL PRIMARY KEY,
+    name TEXT NOT NULL,
+    born TIMESTAMP NOT NULL,
+    score INTEGER NULL,
+    nickname TEXT NULL
+);
+CREATE TABLE orders (
+    id INTEGER NOT NULL PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (id),
+    total REAL NOT NULL
+);
+`
+
+type fixtureUser struct {
+	ID       string        `db:"id"`
+	Name     string        `db:"name"`
+	Born     time.Time     `db:"born"`
+	Score    sql.NullInt64 `db:"score"`
+	Nickname *string       `db:"nickname"`
+}
+
+type fixtureOrder struct {
+	ID     int64   `db:"id"`
+	UserID string  `db:"user_id"`
+	Total  float64 `db:"total"`
+}
+
+type fixtureRepos struct {
+	users    sqlcredo.SQLCredo[fixtureUser, string]
+	orders   sqlcredo.SQLCredo[fixtureOrder, int64]
+	fixtures *sqlcredotest.Fixtures
+}
+
+func newFixtureRepos(t *testing.T, db *sql.DB) fixtureRepos {
+	t.Helper()
+
+	r := fixtureRepos{
+		users:    sqlcredo.NewSQLCredo[fixtureUser, string](db, "sqlite3", "users", "id").WithAmbientTx(true),
+		orders:   sqlcredo.NewSQLCredo[fixtureOrder, int64](db, "sqlite3", "orders", "id").WithAmbientTx(true),
+		fixtures: sqlcredotest.NewFixtures(),
+	}
+	// registered before users to check the dependency order
+	sqlcredotest.RegisterFixture(r.fixtures, "orders", r.orders, "users")
+	sqlcredotest.RegisterFixture(r.fixtures, "users", r.users)
+	return r
+}
+
+func openFixtureDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "fixtures.db")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec(fixtureSchema)
+	require.NoError(t, err)
+	return db
+}
+
+func TestFixtures_Load(t *testing.T) {
+	ctx := context.Background()
+	r := newFixtureRepos(t, openFixtureDB(t))
+
+	require.NoError(t, r.fixtures.Load(ctx, os.DirFS("testdata/fixtures"), "*.json", "*.yaml"))
+
+	users, err := r.users.GetAll(ctx)
+	require.NoError(t, err)
+	johnny := "Johnny"
+	assert.Equal(t, []fixtureUser{
+		{ID: "u1", Name: "John", Born: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+			Score: sql.NullInt64{Int64: 42, Valid: true}, Nickname: &johnny},
+		{ID: "u2", Name: "Jane", Born: time.Date(1985, 11, 2, 0, 0, 0, 0, time.UTC)},
+	}, users)
+
+	orders, err := r.orders.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []fixtureOrder{{ID: 1, UserID: "u1", Total: 19.99}, {ID: 2, UserID: "u2", Total: 5}}, orders)
+}
+
+func TestFixtures_Load_Errors(t *testing.T) {
+	ctx := context.Background()
+	r := newFixtureRepos(t, openFixtureDB(t))
+	fsys := fstest.MapFS{
+		"unknown_table.yaml":  {Data: []byte("payments:\n  - id: 1\n")},
+		"unknown_column.yaml": {Data: []byte("users:\n  - id: u1\n    email: a@b.c\n")},
+		"bad_type.yaml":       {Data: []byte("orders:\n  - id: one\n")},
+		"users.csv":           {Data: []byte("id,name\n")},
+	}
+
+	assert.ErrorContains(t, r.fixtures.Load(ctx, fsys, "unknown_table.yaml"), `table "payments" is not registered`)
+	assert.ErrorIs(t, r.fixtures.Load(ctx, fsys, "unknown_column.yaml"), api.ErrUnknownColumn)
+	assert.ErrorContains(t, r.fixtures.Load(ctx, fsys, "bad_type.yaml"), `unable to decode column "id"`)
+	assert.ErrorContains(t, r.fixtures.Load(ctx, fsys, "users.csv"), "not a YAML or JSON file")
+	assert.ErrorContains(t, r.fixtures.Load(ctx, fsys, "missing/*.yaml"), "no matching files")
+}
+
+func TestFixtures_Load_Cycle(t *testing.T) {
+	r := newFixtureRepos(t, openFixtureDB(t))
+	sqlcredotest.RegisterFixture(r.fixtures, "users", r.users, "orders")
+
+	err := r.fixtures.Load(context.Background(), os.DirFS("testdata/fixtures"), "*.yaml")
+
+	assert.ErrorContains(t, err, "dependency cycle [orders users orders]")
+}
+
+func TestRollbackTx(t *testing.T) {
+	db := openFixtureDB(t)
+	r := newFixtureRepos(t, db)
+
+	t.Run("load", func(t *testing.T) {
+		ctx := sqlcredotest.RollbackTx(t, db)
+		require.NoError(t, r.fixtures.Load(ctx, os.DirFS("testdata/fixtures"), "*.yaml"))
+
+		count, err := r.users.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), count)
+	})
+
+	count, err := r.users.Count(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestSQLiteSnapshot(t *testing.T) {
+	ctx := context.Background()
+	db := openFixtureDB(t)
+	require.NoError(t, newFixtureRepos(t, db).fixtures.Load(ctx, os.DirFS("testdata/fixtures"), "*"))
+
+	snapshot, err := sqlcredotest.NewSQLiteSnapshot(ctx, db, filepath.Join(t.TempDir(), "snapshot.db"))
+	require.NoError(t, err)
+
+	for _, name := range []string{"first", "second"} {
+		t.Run(name, func(t *testing.T) {
+			r := newFixtureRepos(t, snapshot.Open(t))
+
+			count, err := r.orders.Count(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(2), count)
+
+			_, err = r.orders.DeleteAll(ctx)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package sqlcredotest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/internal/goquext"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden
+// rewrite the golden files instead of comparing with them, e.g.
+//
+//	SQLCREDOTEST_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "SQLCREDOTEST_UPDATE_GOLDEN"
+
+// AssertGolden compares the recorded statements with the golden file
+// testdata/<name>.<dialect>.golden, so changes of the generated SQL, e.g. after
+// upgrading goqu, show up as test failures with a diff.
+func (r *Recorder) AssertGolden(t testing.TB, name string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+"."+goquext.CreateDialectString(r.driver)+".golden")
+	got := r.Golden()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run with %s=1 to create the golden file", UpdateGoldenEnv)
+	assert.Equal(t, string(want), got, "generated SQL differs from %s", path)
+}
+
+// Golden returns the recorded statements in the golden file format:
+// one statement per line, followed by an indented line with its arguments.
+func (r *Recorder) Golden() string {
+	var b strings.Builder
+	for _, s := range r.Statements() {
+		if s.Tx > 0 {
+			b.WriteString("[tx] ")
+		}
+		b.WriteString(s.SQL)
+		b.WriteString("\n")
+		if len(s.Args) > 0 {
+			args := make([]string, 0, len(s.Args))
+			for _, a := range s.Args {
+				args = append(args, formatArg(a))
+			}
+			b.WriteString("    args: " + strings.Join(args, ", ") + "\n")
+		}
+	}
+	return b.String()
+}
+
+func formatArg(a any) string {
+	switch v := a.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []byte:
+		return fmt.Sprintf("x%q", v)
+	case nil:
+		return "NULL"
+	}
+	return fmt.Sprintf("%v", a)
+}
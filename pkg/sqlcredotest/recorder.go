@@ -0,0 +1,358 @@
+package sqlcredotest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/Klojer/sqlcredo/internal/sqlexec"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Statement is a statement sent to the database through a Recorder.
+// Transactions are recorded as "BEGIN", "COMMIT" and "ROLLBACK" statements.
+type Statement struct {
+	SQL  string
+	Args []any // Arguments as sent to the driver, e.g. int64 for all integers
+	Tx   int   // Sequence number of the enclosing transaction, 0 outside transactions
+}
+
+// Recorder is a fake database that records every statement and answers
+// them with stubbed results. Pass Recorder.DB to sqlcredo.NewSQLCredo with
+// the driver name of the dialect to test, so repositories build their
+// queries exactly as for the real database:
+//
+//	rec := sqlcredotest.NewRecorder("pgx")
+//	repo := sqlcredo.NewSQLCredo[User, string](rec.DB(), rec.Driver(), "users", "id")
+//	rec.Stub(`FROM "users"`).Rows([]string{"id", "name"}, []any{"u1", "John"})
+//
+// Queries without a matching stub return no rows; statements affect no rows.
+type Recorder struct {
+	driver string
+	db     *sql.DB
+
+	mu         sync.Mutex
+	statements []Statement
+	stubs      []*Stub
+	txCount    int
+}
+
+// NewRecorder creates a Recorder for queries of the given driver,
+// e.g. "sqlite3", "postgres" or "pgx".
+func NewRecorder(driverName string) *Recorder {
+	r := &Recorder{driver: driverName}
+	r.db = sql.OpenDB(connector{r})
+	return r
+}
+
+// DB returns the database handle recording all statements.
+func (r *Recorder) DB() *sql.DB {
+	return r.db
+}
+
+// Driver returns the driver name the recorder was created with.
+func (r *Recorder) Driver() string {
+	return r.driver
+}
+
+// Executor returns an executor on DB for code depending on api.SQLExecutor;
+// its statements and transactions are recorded like the ones of DB.
+func (r *Recorder) Executor() api.SQLExecutor {
+	return sqlexec.NewSQLExecutor(sqlx.NewDb(r.db, r.driver))
+}
+
+// Statements returns the recorded statements in execution order.
+func (r *Recorder) Statements() []Statement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.statements)
+}
+
+// SQL returns the SQL of the recorded statements in execution order.
+func (r *Recorder) SQL() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]string, 0, len(r.statements))
+	for _, s := range r.statements {
+		res = append(res, s.SQL)
+	}
+	return res
+}
+
+// Reset forgets the recorded statements; stubs are kept.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = nil
+}
+
+// Stub answers the statements matching the regular expression pattern.
+// When several stubs match, the latest one wins, so tests can override
+// stubs of a shared setup.
+func (r *Recorder) Stub(pattern string) *Stub {
+	s := &Stub{pattern: regexp.MustCompile(pattern)}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stubs = append(r.stubs, s)
+	return s
+}
+
+func (r *Recorder) record(sql string, args []driver.NamedValue, tx int) *Stub {
+	var values []any
+	for _, a := range args {
+		values = append(values, a.Value)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statements = append(r.statements, Statement{SQL: sql, Args: values, Tx: tx})
+	for i := len(r.stubs) - 1; i >= 0; i-- {
+		if r.stubs[i].pattern.MatchString(sql) {
+			return r.stubs[i]
+		}
+	}
+	return &Stub{}
+}
+
+func (r *Recorder) begin() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txCount++
+	return r.txCount
+}
+
+// Stub is the answer to the statements matching a pattern.
+type Stub struct {
+	mu           sync.Mutex
+	pattern      *regexp.Regexp
+	columns      []string
+	rows         [][]driver.Value
+	rowsAffected int64
+	lastInsertID int64
+	err          error
+}
+
+// Rows sets the result of matching queries. Values are converted like query arguments,
+// so rows may hold any type the driver accepts, e.g. int or time.Time.
+func (s *Stub) Rows(columns []string, rows ...[]any) *Stub {
+	converted := make([][]driver.Value, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != len(columns) {
+			panic(fmt.Sprintf("sqlcredotest: stub row has %d values for %d columns", len(row), len(columns)))
+		}
+		values := make([]driver.Value, 0, len(row))
+		for _, v := range row {
+			dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+			if err != nil {
+				panic(fmt.Sprintf("sqlcredotest: stub value %v: %v", v, err))
+			}
+			values = append(values, dv)
+		}
+		converted = append(converted, values)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.columns, s.rows = columns, converted
+	return s
+}
+
+// Result sets the result of matching statements.
+func (s *Stub) Result(rowsAffected, lastInsertID int64) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rowsAffected, s.lastInsertID = rowsAffected, lastInsertID
+	return s
+}
+
+// Error makes matching statements fail with err.
+func (s *Stub) Error(err error) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	return s
+}
+
+type connector struct {
+	r *Recorder
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{r: c.r}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return recorderDriver{c.r}
+}
+
+type recorderDriver struct {
+	r *Recorder
+}
+
+func (d recorderDriver) Open(string) (driver.Conn, error) {
+	return &conn{r: d.r}, nil
+}
+
+type conn struct {
+	r  *Recorder
+	tx int
+}
+
+var (
+	_ driver.ConnBeginTx        = &conn{}
+	_ driver.ExecerContext      = &conn{}
+	_ driver.QueryerContext     = &conn{}
+	_ driver.ConnPrepareContext = &conn{}
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c: c, query: query}, nil
+}
+
+func (c *conn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return &stmt{c: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.tx = c.r.begin()
+	if s := c.r.record("BEGIN", nil, c.tx); s.failure() != nil {
+		c.tx = 0
+		return nil, s.failure()
+	}
+	return tx{c}, nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.r.record(query, args, c.tx).result()
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.r.record(query, args, c.tx).query()
+}
+
+type tx struct {
+	c *conn
+}
+
+func (t tx) Commit() error {
+	return t.end("COMMIT")
+}
+
+func (t tx) Rollback() error {
+	return t.end("ROLLBACK")
+}
+
+func (t tx) end(statement string) error {
+	s := t.c.r.record(statement, nil, t.c.tx)
+	t.c.tx = 0
+	return s.failure()
+}
+
+type stmt struct {
+	c     *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.c.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.c.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	res := make([]driver.NamedValue, 0, len(args))
+	for i, a := range args {
+		res = append(res, driver.NamedValue{Ordinal: i + 1, Value: a})
+	}
+	return res
+}
+
+func (s *Stub) failure() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *Stub) result() (driver.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return stubResult{rowsAffected: s.rowsAffected, lastInsertID: s.lastInsertID}, nil
+}
+
+func (s *Stub) query() (driver.Rows, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &rows{columns: s.columns, values: s.rows}, nil
+}
+
+type stubResult struct {
+	rowsAffected int64
+	lastInsertID int64
+}
+
+func (r stubResult) LastInsertId() (int64, error) { return r.lastInsertID, nil }
+func (r stubResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,109 @@
+package sqlcredotest_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+	"github.com/Klojer/sqlcredo/pkg/sqlcredotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestRecorder_Golden(t *testing.T) {
+	for _, driver := range []string{"sqlite3", "pgx"} {
+		t.Run(driver, func(t *testing.T) {
+			ctx := context.Background()
+			rec := sqlcredotest.NewRecorder(driver)
+			repo := sqlcredo.NewSQLCredo[user, string](rec.DB(), rec.Driver(), "users", "id")
+			rec.Stub(`^SELECT COUNT`).Rows([]string{"count"}, []any{0})
+
+			_, err := repo.Create(ctx, &user{ID: "u1", Name: "John"})
+			require.NoError(t, err)
+			_, err = repo.Update(ctx, "u1", &user{ID: "u1", Name: "Johnny"})
+			require.NoError(t, err)
+			_, _ = repo.GetByID(ctx, "u1")
+			_, err = repo.GetPage(ctx, api.WithPageSize(5), api.WithSortBy("name"),
+				api.WithFilter(api.Like("name", "J%")))
+			require.NoError(t, err)
+			_, err = repo.Delete(ctx, "u1")
+			require.NoError(t, err)
+
+			rec.AssertGolden(t, "crud")
+		})
+	}
+}
+
+func TestRecorder_Stub(t *testing.T) {
+	ctx := context.Background()
+	rec := sqlcredotest.NewRecorder("pgx")
+	repo := sqlcredo.NewSQLCredo[user, string](rec.DB(), rec.Driver(), "users", "id")
+
+	rec.Stub(`^SELECT .* FROM "users"`).Rows([]string{"id", "name"}, []any{"u1", "John"})
+	rec.Stub(`^SELECT COUNT`).Rows([]string{"count"}, []any{42})
+	errDuplicate := errors.New("duplicate key")
+	rec.Stub(`^INSERT`).Error(errDuplicate)
+	rec.Stub(`^DELETE`).Result(1, 0)
+
+	got, err := repo.GetByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, user{ID: "u1", Name: "John"}, got)
+
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), count)
+
+	_, err = repo.Create(ctx, &user{ID: "u1"})
+	assert.ErrorIs(t, err, errDuplicate)
+
+	res, err := repo.Delete(ctx, "u1")
+	require.NoError(t, err)
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	// the latest matching stub wins
+	rec.Stub(`^SELECT .* FROM "users"`).Rows([]string{"id", "name"})
+	_, err = repo.GetByID(ctx, "u1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRecorder_Transactions(t *testing.T) {
+	ctx := context.Background()
+	rec := sqlcredotest.NewRecorder("sqlite3")
+	repo := sqlcredo.NewSQLCredo[user, string](rec.DB(), rec.Driver(), "users", "id")
+
+	err := repo.InTx(ctx, nil, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "UPDATE users SET name = ? WHERE id = ?", "John", "u1")
+		return err
+	})
+	require.NoError(t, err)
+
+	errFailed := errors.New("failed")
+	err = repo.InTx(ctx, nil, func(context.Context, *sql.Tx) error { return errFailed })
+	assert.ErrorIs(t, err, errFailed)
+
+	_, err = rec.Executor().Exec(ctx, "DELETE FROM users")
+	require.NoError(t, err)
+
+	assert.Equal(t, []sqlcredotest.Statement{
+		{SQL: "BEGIN", Tx: 1},
+		{SQL: "UPDATE users SET name = ? WHERE id = ?", Args: []any{"John", "u1"}, Tx: 1},
+		{SQL: "COMMIT", Tx: 1},
+		{SQL: "BEGIN", Tx: 2},
+		{SQL: "ROLLBACK", Tx: 2},
+		{SQL: "DELETE FROM users"},
+	}, rec.Statements())
+
+	rec.Reset()
+	assert.Empty(t, rec.SQL())
+}
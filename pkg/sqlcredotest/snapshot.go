@@ -0,0 +1,82 @@
+package sqlcredotest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RollbackTx begins a transaction on db, rolled back when t ends, and returns
+// a context carrying it. Repositories with WithAmbientTx(true) run the queries
+// of the context in the transaction, so tests sharing db don't see each other's data.
+func RollbackTx(t *testing.T, db *sql.DB) context.Context {
+	t.Helper()
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, tx.Rollback())
+	})
+
+	return api.WithTxContext(context.Background(), tx)
+}
+
+// SQLiteSnapshot is a copy of a sqlite database, e.g. after running
+// migrations and loading fixtures, that tests open fresh copies of.
+type SQLiteSnapshot struct {
+	path string
+}
+
+// NewSQLiteSnapshot writes a consistent copy of the sqlite database db to path,
+// which must not exist.
+func NewSQLiteSnapshot(ctx context.Context, db *sql.DB, path string) (*SQLiteSnapshot, error) {
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return nil, fmt.Errorf("unable to snapshot sqlite database: %w", err)
+	}
+	return &SQLiteSnapshot{path: path}, nil
+}
+
+// Path returns the path of the snapshot file.
+func (s *SQLiteSnapshot) Path() string {
+	return s.path
+}
+
+// Open copies the snapshot to a temporary file of t and opens it with the
+// sqlite3 driver, which the caller registers. The database is closed when t ends.
+func (s *SQLiteSnapshot) Open(t *testing.T) *sql.DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), filepath.Base(s.path))
+	require.NoError(t, copyFile(s.path, path))
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("unable to open snapshot: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("unable to restore snapshot: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("unable to restore snapshot: %w", err)
+	}
+	return out.Close()
+}
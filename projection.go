@@ -0,0 +1,47 @@
+package sqlcredo
+
+import (
+	"context"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// GetAllAs loads all records as projection type P instead of the entity type.
+// Only the columns mapped by the db tags of P are selected, unless api.Select is given.
+//
+// Example:
+//
+//	type UserSummary struct {
+//		ID   string `db:"id"`
+//		Name string `db:"name"`
+//	}
+//	summaries, err := sqlcredo.GetAllAs[UserSummary](ctx, repo)
+func GetAllAs[P any](ctx context.Context, r api.Projector, opts ...api.QueryOpt) ([]P, error) {
+	var records []P
+	if err := r.GetAllInto(ctx, &records, opts...); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetPageAs loads a single page of records as projection type P instead of the entity type.
+// Only the columns mapped by the db tags of P are selected, unless api.WithColumns is given.
+func GetPageAs[P any](ctx context.Context, r api.Projector, opts ...api.PageOpt) (api.Page[P], error) {
+	var records []P
+	info, err := r.GetPageInto(ctx, &records, opts...)
+	if err != nil {
+		return api.Page[P]{}, err
+	}
+
+	if len(records) == 0 {
+		return api.Page[P]{}, nil
+	}
+
+	return api.Page[P]{
+		Number:     info.Number,
+		Size:       info.Size,
+		Total:      info.Total,
+		TotalPages: info.TotalPages,
+		Content:    records,
+	}, nil
+}
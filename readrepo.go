@@ -0,0 +1,125 @@
+package sqlcredo
+
+import (
+	"database/sql"
+
+	"github.com/Klojer/sqlcredo/internal/crud"
+	"github.com/Klojer/sqlcredo/internal/goquext"
+	"github.com/Klojer/sqlcredo/internal/page"
+	"github.com/Klojer/sqlcredo/internal/sqlexec"
+	"github.com/Klojer/sqlcredo/internal/table"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+)
+
+// ReadRepo is a read-only repository over a table, a view or an arbitrary query,
+// typically a join of several tables. It provides the read operations of SQLCredo,
+// including paging, projections and filtering, while write operations are not part
+// of the interface and are therefore rejected at compile time.
+//
+// Type Parameters:
+//   - T: The read model type being loaded
+//   - I: The type of the read model's ID field (must be comparable)
+type ReadRepo[T any, I comparable] interface {
+	api.Reader[T, I]
+	api.PageResolver[T]
+	api.Projector
+
+	// WithDebugFunc sets a debug function for SQL query logging.
+	// Returns the modified ReadRepo instance for method chaining.
+	WithDebugFunc(newDebugFunc api.DebugFunc) ReadRepo[T, I]
+
+	// GetDebugFunc returns the currently set debug function.
+	GetDebugFunc() api.DebugFunc
+}
+
+// Source describes where a ReadRepo loads its records from.
+type Source struct {
+	name string
+	expr func(dialect string) exp.Expression
+}
+
+// FromView reads records from a table or a view with the given name.
+func FromView(name string) Source {
+	return Source{name: name}
+}
+
+// FromQuery reads records from the result of a goqu select query,
+// which is used as a subquery aliased with the given name.
+// The query is rendered with the dialect of the repository.
+//
+// Example:
+//
+//	query := goqu.From("users").
+//		Join(goqu.T("orgs"), goqu.On(goqu.I("users.org_id").Eq(goqu.I("orgs.id")))).
+//		Select("users.id", "users.name", goqu.I("orgs.name").As("org_name"))
+//	repo := sqlcredo.NewReadRepo[UserWithOrg, string](db, "sqlite3",
+//		sqlcredo.FromQuery("user_orgs", query), "id")
+func FromQuery(alias string, query *goqu.SelectDataset) Source {
+	return Source{
+		name: alias,
+		expr: func(dialect string) exp.Expression {
+			return query.WithDialect(dialect).As(alias)
+		},
+	}
+}
+
+// FromSQL reads records from the result of a raw SQL query,
+// which is used as a subquery aliased with the given name.
+// Arguments must be referenced with "?" placeholders, which are
+// rebound to the placeholder style of the driver.
+func FromSQL(alias string, query string, args ...any) Source {
+	return Source{
+		name: alias,
+		expr: func(string) exp.Expression {
+			return goqu.L("("+query+")", args...).As(alias)
+		},
+	}
+}
+
+type readRepo[T any, I comparable] struct {
+	executor *sqlexec.SQLExecutor
+	*crud.Reader[T, I]
+	*page.PageResolver[T]
+}
+
+var _ ReadRepo[any, string] = &readRepo[any, string]{}
+
+// NewReadRepo creates a new read-only repository for the specified read model type and ID type.
+//
+// Parameters:
+//   - db: A pointer to the underlying database connection
+//   - driver: The database driver name (e.g., "sqlite3", "pgx")
+//   - source: The table, view or query to read records from
+//   - idColumn: The name of the column identifying records of the source
+//
+// Returns a fully initialized ReadRepo instance
+func NewReadRepo[T any, I comparable](db *sql.DB, driver string, source Source, idColumn string) ReadRepo[T, I] {
+	tableInfo := table.Info{Name: source.name, IDColumn: idColumn}
+	if source.expr != nil {
+		tableInfo.Source = source.expr(goquext.CreateDialectString(driver))
+	}
+
+	dbx := sqlx.NewDb(db, driver)
+	executor := sqlexec.NewSQLExecutor(dbx)
+
+	return &readRepo[T, I]{
+		executor:     executor,
+		Reader:       crud.NewReader[T, I](tableInfo, executor, driver),
+		PageResolver: page.NewPageResolver[T](tableInfo, executor, driver),
+	}
+}
+
+// WithDebugFunc sets a new debug function for SQL query logging.
+func (r *readRepo[T, I]) WithDebugFunc(newDebugFunc api.DebugFunc) ReadRepo[T, I] {
+	r.executor.DebugFunc = newDebugFunc
+	return r
+}
+
+// GetDebugFunc returns the currently set debug function.
+func (r *readRepo[T, I]) GetDebugFunc() api.DebugFunc {
+	return r.executor.DebugFunc
+}
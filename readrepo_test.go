@@ -0,0 +1,88 @@
+package sqlcredo_test
+
+import (
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memberWithOrg struct {
+	ID      string  `db:"id"`
+	Name    string  `db:"name"`
+	OrgName *string `db:"org_name"`
+}
+
+func TestReadRepo_FromQuery(t *testing.T) {
+	c, ctx := newRelationTestCase(t)
+
+	query := goqu.From("members").
+		LeftJoin(goqu.T("orgs"), goqu.On(goqu.I("members.org_id").Eq(goqu.I("orgs.id")))).
+		Select(goqu.I("members.id"), goqu.I("members.name"), goqu.I("orgs.name").As("org_name"))
+	repo := sqlcredo.NewReadRepo[memberWithOrg, string](c.DB, "sqlite3",
+		sqlcredo.FromQuery("member_orgs", query), "id")
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "Acme", *all[0].OrgName)
+	assert.Nil(t, all[2].OrgName)
+
+	got, err := repo.GetByID(ctx, "m2")
+	require.NoError(t, err)
+	assert.Equal(t, "Ann", got.Name)
+
+	page, err := repo.GetPage(ctx, api.WithPageSize(1), api.WithPageNumber(1),
+		api.WithFilter(api.IsNotNull("org_name")))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), page.Total)
+	assert.Equal(t, uint(2), page.TotalPages)
+	require.Len(t, page.Content, 1)
+	assert.Equal(t, "m2", page.Content[0].ID)
+
+	cnt, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), cnt)
+
+	exists, err := repo.Exists(ctx, api.Eq("org_name", "Globex"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestReadRepo_FromSQL(t *testing.T) {
+	c, ctx := newRelationTestCase(t)
+
+	repo := sqlcredo.NewReadRepo[address, int](c.DB, "sqlite3",
+		sqlcredo.FromSQL("member_addresses",
+			"SELECT * FROM addresses WHERE member_id = ?", "m1"), "id")
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	cnt, err := repo.CountWhere(ctx, api.Eq("city", "Paris"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), cnt)
+}
+
+func TestReadRepo_FromView(t *testing.T) {
+	c, ctx := newRelationTestCase(t)
+
+	_, err := c.DB.ExecContext(ctx,
+		"CREATE VIEW berlin_addresses AS SELECT * FROM addresses WHERE city = 'Berlin'")
+	require.NoError(t, err)
+
+	repo := sqlcredo.NewReadRepo[address, int](c.DB, "sqlite3",
+		sqlcredo.FromView("berlin_addresses"), "id")
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []address{{ID: 1, MemberID: "m1", City: "Berlin"}}, all)
+
+	_, isWriter := any(repo).(api.CRUD[address, int])
+	assert.False(t, isWriter)
+}
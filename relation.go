@@ -0,0 +1,205 @@
+package sqlcredo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Klojer/sqlcredo/internal/relation"
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// Relation declares how entities of a repository reference entities of another repository.
+// Relations are registered with SQLCredo.WithRelations and loaded on demand with
+// api.Preload or api.WithPreload, issuing one IN query per relation and
+// 1000 keys, which keeps the queries within bind parameter limits.
+//
+// The relation field must not be mapped to a column, so tag it with `db:"-"`.
+type Relation struct {
+	field string
+	load  func(ctx context.Context, parents reflect.Value, parentIDColumn string) error
+}
+
+// HasMany declares a one-to-many relation: the target table references the parent
+// through its foreignKey column. Matching records are assigned to field of the parent,
+// which must be of type []C or []*C.
+//
+// Example:
+//
+//	type User struct {
+//		ID        string    `db:"id"`
+//		Addresses []Address `db:"-"`
+//	}
+//	users := sqlcredo.NewSQLCredo[User, string](db, driver, "users", "id").
+//		WithRelations(sqlcredo.HasMany("Addresses", addresses, "user_id"))
+//	all, err := users.GetAll(ctx, api.Preload("Addresses"))
+func HasMany[C any, J comparable](field string, target SQLCredo[C, J], foreignKey string) Relation {
+	return Relation{
+		field: field,
+		load: func(ctx context.Context, parents reflect.Value, parentIDColumn string) error {
+			keys, err := relation.Keys(parents, parentIDColumn)
+			if err != nil || len(keys) == 0 {
+				return err
+			}
+
+			children, err := loadRelated(ctx, target, foreignKey, keys)
+			if err != nil {
+				return err
+			}
+
+			return relation.AssignMany(parents, field, parentIDColumn,
+				reflect.ValueOf(children), foreignKey)
+		},
+	}
+}
+
+// BelongsTo declares a many-to-one relation: the parent table references the target
+// through its foreignKey column. The matching record is assigned to field of the parent,
+// which must be of type C or *C.
+func BelongsTo[C any, J comparable](field string, target SQLCredo[C, J], foreignKey string) Relation {
+	return Relation{
+		field: field,
+		load: func(ctx context.Context, parents reflect.Value, _ string) error {
+			keys, err := relation.Keys(parents, foreignKey)
+			if err != nil || len(keys) == 0 {
+				return err
+			}
+
+			targetIDColumn := target.IDColumn()
+			children, err := loadRelated(ctx, target, targetIDColumn, keys)
+			if err != nil {
+				return err
+			}
+
+			return relation.AssignOne(parents, field, foreignKey,
+				reflect.ValueOf(children), targetIDColumn)
+		},
+	}
+}
+
+// preloadChunkSize is the maximum number of keys per preload query.
+const preloadChunkSize = 1000
+
+// loadRelated loads the records of target whose column matches one of keys.
+func loadRelated[C any, J comparable](ctx context.Context, target SQLCredo[C, J], column string, keys []any) ([]C, error) {
+	var res []C
+	for start := 0; start < len(keys); start += preloadChunkSize {
+		chunk := keys[start:min(start+preloadChunkSize, len(keys))]
+		records, err := target.GetAll(ctx, api.Where(api.In(column, chunk...)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load related records: %w", err)
+		}
+		res = append(res, records...)
+	}
+	return res, nil
+}
+
+// WithRelations registers relations that can be preloaded by the read operations.
+// A relation replaces a previously registered one for the same field.
+func (r *sqlCredo[T, I]) WithRelations(relations ...Relation) SQLCredo[T, I] {
+	if r.relations == nil {
+		r.relations = make(map[string]Relation, len(relations))
+	}
+	for _, rel := range relations {
+		r.relations[rel.field] = rel
+	}
+	return r
+}
+
+func (r *sqlCredo[T, I]) GetAll(ctx context.Context, opts ...api.QueryOpt) ([]T, error) {
+	records, err := r.CRUD.GetAll(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.preload(ctx, reflect.ValueOf(records), queryPreload(opts)); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *sqlCredo[T, I]) GetAllInto(ctx context.Context, dest any, opts ...api.QueryOpt) error {
+	if err := r.CRUD.GetAllInto(ctx, dest, opts...); err != nil {
+		return err
+	}
+	return r.preload(ctx, reflect.ValueOf(dest).Elem(), queryPreload(opts))
+}
+
+func (r *sqlCredo[T, I]) GetByID(ctx context.Context, id I, opts ...api.QueryOpt) (T, error) {
+	record, err := r.CRUD.GetByID(ctx, id, opts...)
+	if err != nil {
+		return record, err
+	}
+
+	records := []T{record}
+	if err := r.preload(ctx, reflect.ValueOf(records), queryPreload(opts)); err != nil {
+		return record, err
+	}
+	return records[0], nil
+}
+
+func (r *sqlCredo[T, I]) GetByIDs(ctx context.Context, ids []I, opts ...api.QueryOpt) ([]T, error) {
+	records, err := r.CRUD.GetByIDs(ctx, ids, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.preload(ctx, reflect.ValueOf(records), queryPreload(opts)); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *sqlCredo[T, I]) GetPage(ctx context.Context, opts ...api.PageOpt) (api.Page[T], error) {
+	page, err := r.PageResolver.GetPage(ctx, opts...)
+	if err != nil {
+		return page, err
+	}
+	if err := r.preload(ctx, reflect.ValueOf(page.Content), pagePreload(opts)); err != nil {
+		return api.Page[T]{}, err
+	}
+	return page, nil
+}
+
+func (r *sqlCredo[T, I]) GetPageInto(ctx context.Context, dest any, opts ...api.PageOpt) (api.PageInfo, error) {
+	info, err := r.PageResolver.GetPageInto(ctx, dest, opts...)
+	if err != nil {
+		return info, err
+	}
+	if err := r.preload(ctx, reflect.ValueOf(dest).Elem(), pagePreload(opts)); err != nil {
+		return api.PageInfo{}, err
+	}
+	return info, nil
+}
+
+func (r *sqlCredo[T, I]) preload(ctx context.Context, records reflect.Value, fields []string) error {
+	if len(fields) == 0 || records.Len() == 0 {
+		return nil
+	}
+
+	for _, field := range fields {
+		rel, ok := r.relations[field]
+		if !ok {
+			return fmt.Errorf("unable to preload %q: %w", field, api.ErrUnknownRelation)
+		}
+		if err := rel.load(ctx, records, r.IDColumn()); err != nil {
+			return fmt.Errorf("unable to preload %q: %w", field, err)
+		}
+	}
+
+	return nil
+}
+
+func queryPreload(opts []api.QueryOpt) []string {
+	var params api.QueryParams
+	for _, o := range opts {
+		o(&params)
+	}
+	return params.Preload
+}
+
+func pagePreload(opts []api.PageOpt) []string {
+	var params api.PageParams
+	for _, o := range opts {
+		o(&params)
+	}
+	return params.Preload
+}
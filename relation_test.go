@@ -0,0 +1,176 @@
+package sqlcredo_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const relationSchema = `
+CREATE TABLE orgs (id TEXT PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE members (id TEXT PRIMARY KEY, name TEXT NOT NULL, org_id TEXT NULL);
+CREATE TABLE addresses (id INTEGER PRIMARY KEY, member_id TEXT NOT NULL, city TEXT NOT NULL);
+`
+
+type org struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+}
+
+type address struct {
+	ID       int    `db:"id"`
+	MemberID string `db:"member_id"`
+	City     string `db:"city"`
+}
+
+type member struct {
+	ID    string  `db:"id"`
+	Name  string  `db:"name"`
+	OrgID *string `db:"org_id"`
+
+	Org       *org      `db:"-"`
+	Addresses []address `db:"-"`
+}
+
+func TestSQLCredo_Preload(t *testing.T) {
+	c, ctx := newRelationTestCase(t)
+
+	got, err := c.Members.GetAll(ctx, api.Preload("Org", "Addresses"))
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, &org{ID: "o1", Name: "Acme"}, got[0].Org)
+	assert.Equal(t, []address{
+		{ID: 1, MemberID: "m1", City: "Berlin"},
+		{ID: 2, MemberID: "m1", City: "Paris"},
+	}, got[0].Addresses)
+
+	assert.Equal(t, &org{ID: "o1", Name: "Acme"}, got[1].Org)
+	assert.Equal(t, []address{{ID: 3, MemberID: "m2", City: "Rome"}}, got[1].Addresses)
+
+	assert.Nil(t, got[2].Org)
+	assert.Empty(t, got[2].Addresses)
+}
+
+func TestSQLCredo_Preload_GetByIDsAndPage(t *testing.T) {
+	c, ctx := newRelationTestCase(t)
+
+	byIDs, err := c.Members.GetByIDs(ctx, []string{"m2", "m3"}, api.Preload("Addresses"))
+	require.NoError(t, err)
+	require.Len(t, byIDs, 2)
+	assert.Len(t, byIDs[0].Addresses, 1)
+	assert.Nil(t, byIDs[0].Org)
+
+	page, err := c.Members.GetPage(ctx, api.WithPageSize(1), api.WithPreload("Org"))
+	require.NoError(t, err)
+	require.Len(t, page.Content, 1)
+	assert.Equal(t, "Acme", page.Content[0].Org.Name)
+
+	byID, err := c.Members.GetByID(ctx, "m1", api.Preload("Addresses"))
+	require.NoError(t, err)
+	assert.Len(t, byID.Addresses, 2)
+}
+
+func TestSQLCredo_Preload_Chunks(t *testing.T) {
+	c, ctx := newRelationTestCase(t)
+
+	var queries int
+	addresses := sqlcredo.NewSQLCredo[address, int](c.DB, "sqlite3", "addresses", "id").
+		WithDebugFunc(func(query string, _ ...any) {
+			if strings.HasPrefix(query, "SELECT") {
+				queries++
+			}
+		})
+	members := sqlcredo.NewSQLCredo[member, string](c.DB, "sqlite3", "members", "id").
+		WithRelations(sqlcredo.HasMany("Addresses", addresses, "member_id"))
+
+	const n = 1500
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("c%04d", i)
+		_, err := members.Create(ctx, &member{ID: id, Name: "member"})
+		require.NoError(t, err)
+		_, err = addresses.Create(ctx, &address{ID: 100 + i, MemberID: id, City: "Oslo"})
+		require.NoError(t, err)
+	}
+	queries = 0
+
+	got, err := members.GetAll(ctx, api.Where(api.Like("id", "c%")), api.Preload("Addresses"))
+	require.NoError(t, err)
+
+	require.Len(t, got, n)
+	for _, m := range got {
+		require.Len(t, m.Addresses, 1)
+		assert.Equal(t, m.ID, m.Addresses[0].MemberID)
+	}
+	assert.Equal(t, 2, queries)
+}
+
+func TestSQLCredo_Preload_UnknownRelation(t *testing.T) {
+	c, ctx := newRelationTestCase(t)
+
+	_, err := c.Members.GetAll(ctx, api.Preload("Roles"))
+
+	assert.ErrorIs(t, err, api.ErrUnknownRelation)
+}
+
+type relationTestCase struct {
+	DB      *sql.DB
+	Members sqlcredo.SQLCredo[member, string]
+}
+
+func newRelationTestCase(t *testing.T) (*relationTestCase, context.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, db.Close())
+	})
+
+	orgs := sqlcredo.NewSQLCredo[org, string](db, "sqlite3", "orgs", "id")
+	addresses := sqlcredo.NewSQLCredo[address, int](db, "sqlite3", "addresses", "id")
+	members := sqlcredo.NewSQLCredo[member, string](db, "sqlite3", "members", "id").
+		WithRelations(
+			sqlcredo.BelongsTo("Org", orgs, "org_id"),
+			sqlcredo.HasMany("Addresses", addresses, "member_id"),
+		)
+
+	_, err = members.InitSchema(ctx, relationSchema)
+	require.NoError(t, err)
+
+	orgID := "o1"
+	for _, o := range []org{{ID: "o1", Name: "Acme"}, {ID: "o2", Name: "Globex"}} {
+		_, err := orgs.Create(ctx, &o)
+		require.NoError(t, err)
+	}
+	for _, m := range []member{
+		{ID: "m1", Name: "John", OrgID: &orgID},
+		{ID: "m2", Name: "Ann", OrgID: &orgID},
+		{ID: "m3", Name: "Carl"},
+	} {
+		_, err := members.Create(ctx, &m)
+		require.NoError(t, err)
+	}
+	for _, a := range []address{
+		{ID: 1, MemberID: "m1", City: "Berlin"},
+		{ID: 2, MemberID: "m1", City: "Paris"},
+		{ID: 3, MemberID: "m2", City: "Rome"},
+	} {
+		_, err := addresses.Create(ctx, &a)
+		require.NoError(t, err)
+	}
+
+	return &relationTestCase{DB: db, Members: members}, ctx
+}
@@ -0,0 +1,87 @@
+package sqlcredo_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLCredo_Replicas(t *testing.T) {
+	ctx := context.Background()
+	primary := openSQLiteFile(t, "primary.db")
+	replica := openSQLiteFile(t, "replica.db")
+
+	repo := sqlcredo.NewSQLCredo[TestEntity, string](primary, "sqlite3", "test_table", "id").
+		WithReplicas(replica)
+
+	// the replica lags behind and doesn't have the new record yet
+	_, err := repo.Create(ctx, &TestEntity{ID: "1", Name: "created"})
+	require.NoError(t, err)
+
+	_, err = repo.GetByID(ctx, "1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	e, err := repo.GetByID(api.WithPrimary(ctx), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "created", e.Name)
+}
+
+func TestSQLCredo_ReplicaPicker(t *testing.T) {
+	ctx := context.Background()
+	primary := openSQLiteFile(t, "primary.db")
+	first := openSQLiteFile(t, "first.db")
+	second := openSQLiteFile(t, "second.db")
+	_, err := second.Exec("INSERT INTO test_table (id, name) VALUES ('1', 'second')")
+	require.NoError(t, err)
+
+	repo := sqlcredo.NewSQLCredo[TestEntity, string](primary, "sqlite3", "test_table", "id").
+		WithReplicas(first, second).
+		WithReplicaPicker(func(_ context.Context, n int) int { return n - 1 })
+
+	page, err := repo.GetPage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []TestEntity{{ID: "1", Name: "second"}}, page.Content)
+}
+
+// openSQLite opens an in-memory sqlite database, closed when t ends.
+// A single connection keeps all queries on the same database.
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return db
+}
+
+func openSQLiteFile(t *testing.T, name string) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), name))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	_, err = db.Exec("CREATE TABLE test_table (id TEXT PRIMARY KEY, name TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	return db
+}
@@ -0,0 +1,14 @@
+package sqlcredo
+
+import (
+	"github.com/Klojer/sqlcredo/internal/retry"
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// DefaultRetryPolicy returns the retry policy used by default for the driver:
+// three attempts with a jittered exponential backoff, retrying serialization
+// failures and deadlocks on pgx/postgres and SQLITE_BUSY/SQLITE_LOCKED on sqlite3.
+// Other drivers are not retried.
+func DefaultRetryPolicy(driver string) api.RetryPolicy {
+	return retry.DefaultPolicy(driver)
+}
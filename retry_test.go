@@ -0,0 +1,43 @@
+package sqlcredo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLCredo_InTx(t *testing.T) {
+	c, ctx := newTestCase(t)
+	_, err := c.UnderTest.InitSchema(ctx, "CREATE TABLE test_table (id TEXT PRIMARY KEY, name TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	errConflict := errors.New("conflict")
+	c.UnderTest.WithRetryPolicy(api.RetryPolicy{
+		MaxAttempts: 3,
+		Retryable:   func(err error) bool { return errors.Is(err, errConflict) },
+	})
+
+	attempts := 0
+	err = c.UnderTest.InTx(ctx, nil, func(ctx context.Context, tx *sql.Tx) error {
+		attempts++
+		if _, err := tx.ExecContext(ctx, "INSERT INTO test_table (id, name) VALUES (?, ?)", "1", "one"); err != nil {
+			return err
+		}
+		if attempts < 2 {
+			return errConflict
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	count, err := c.UnderTest.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+}
@@ -0,0 +1,54 @@
+package sqlcredo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaKey struct{}
+
+func TestSQLCredo_SchemaResolver(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	// attached sqlite databases act as schemas
+	for _, schema := range []string{"tenant_1", "tenant_2"} {
+		_, err := db.Exec("ATTACH DATABASE ':memory:' AS " + schema)
+		require.NoError(t, err)
+		_, err = db.Exec("CREATE TABLE " + schema + ".test_table (id TEXT PRIMARY KEY, name TEXT NOT NULL)")
+		require.NoError(t, err)
+	}
+
+	repo := sqlcredo.NewSQLCredo[TestEntity, string](db, "sqlite3", "test_table", "id").
+		WithSchema("tenant_1").
+		WithSchemaResolver(func(ctx context.Context) string {
+			schema, _ := ctx.Value(schemaKey{}).(string)
+			return schema
+		})
+	tenant2 := context.WithValue(ctx, schemaKey{}, "tenant_2")
+
+	_, err := repo.Create(ctx, &TestEntity{ID: "1", Name: "one"})
+	require.NoError(t, err)
+	_, err = repo.Create(tenant2, &TestEntity{ID: "2", Name: "two"})
+	require.NoError(t, err)
+
+	all, err := repo.GetAll(tenant2)
+	require.NoError(t, err)
+	assert.Equal(t, []TestEntity{{ID: "2", Name: "two"}}, all)
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	_, err = repo.DeleteAll(tenant2)
+	require.NoError(t, err)
+	count, err = repo.Count(tenant2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	_, err = repo.GetByID(ctx, "1")
+	assert.NoError(t, err)
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 
 	"github.com/Klojer/sqlcredo/internal/crud"
+	"github.com/Klojer/sqlcredo/internal/ddl"
 	"github.com/Klojer/sqlcredo/internal/page"
 	"github.com/Klojer/sqlcredo/internal/sqlexec"
 	"github.com/Klojer/sqlcredo/internal/table"
@@ -22,13 +24,25 @@ import (
 //   - I: The type of the entity's ID field (must be comparable)
 type SQLCredo[T any, I comparable] interface {
 	api.SQLExecutor
+	api.NamedSQLExecutor
 	api.CRUD[T, I]
 	api.PageResolver[T]
+	api.Projector
 
 	// InitSchema executes a SQL query to initialize the database schema.
 	// Typically used for creating tables and other database objects.
 	InitSchema(ctx context.Context, sql string) (sql.Result, error)
 
+	// Patch sets the columns of the patch on the record identified by id,
+	// leaving the other columns unchanged.
+	Patch(ctx context.Context, id I, patch Patch[T]) (sql.Result, error)
+
+	// Verify compares T with the live table and returns an *api.SchemaDriftError
+	// listing missing columns, nullability mismatches, a wrong primary key and
+	// unmapped NOT NULL columns without defaults. Returns nil if they match.
+	// Typically called at startup or in tests.
+	Verify(ctx context.Context) error
+
 	// WithDebugFunc sets a debug function for SQL query logging.
 	// The debug function will be called before executing any SQL query.
 	// Returns the modified SQLCredo instance for method chaining.
@@ -37,12 +51,97 @@ type SQLCredo[T any, I comparable] interface {
 	// GetDebugFunc returns the currently set debug function.
 	// Returns nil if no debug function is set.
 	GetDebugFunc() api.DebugFunc
+
+	// WithStatementCache makes the instance reuse prepared statements for up to
+	// size distinct queries, evicting the least recently used ones.
+	// Statements are prepared again transparently after a connection loss.
+	// Queries run in an ambient transaction don't use the cache.
+	// A size of zero or less disables the cache.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithStatementCache(size int) SQLCredo[T, I]
+
+	// StatementCacheStats returns the counters of the prepared statement cache.
+	StatementCacheStats() api.StatementCacheStats
+
+	// WithReplicas routes reads (GetAll, GetByID, GetByIDs, GetPage, Count,
+	// SelectOne, SelectMany and their variants) to the given read replicas,
+	// while writes and BeginTx use the primary database.
+	// Use api.WithPrimary to force reads of a context to the primary.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithReplicas(replicas ...*sql.DB) SQLCredo[T, I]
+
+	// WithReplicaPicker sets the strategy choosing the replica for each read.
+	// The default is api.RoundRobin.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithReplicaPicker(picker api.ReplicaPicker) SQLCredo[T, I]
+
+	// Begin starts a transaction on the primary database,
+	// wrapped for savepoint support.
+	Begin(ctx context.Context, opts *sql.TxOptions) (*Tx, error)
+
+	// WithRetryPolicy sets the policy retrying reads and transactions run with InTx
+	// on transient errors. Defaults to DefaultRetryPolicy for the driver.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithRetryPolicy(policy api.RetryPolicy) SQLCredo[T, I]
+
+	// InTx runs fn in a transaction, committing it if fn succeeds and rolling it back
+	// otherwise. On transient errors the whole transaction is retried according to
+	// the retry policy, so fn must be safe to call multiple times.
+	InTx(ctx context.Context, opts *sql.TxOptions, fn api.TxFunc) error
+
+	// WithTimeouts sets default timeouts per query kind, applied to queries
+	// whose context has no deadline.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithTimeouts(timeouts api.Timeouts) SQLCredo[T, I]
+
+	// WithMaxRows limits the number of records GetAll and GetAllInto may load;
+	// exceeding the limit fails with api.ErrTooManyRows. Zero means no limit.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithMaxRows(maxRows uint) SQLCredo[T, I]
+
+	// WithAmbientTx makes the instance run its queries in the transaction of
+	// the context (see WithTxContext), and InTx use savepoints when nested.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithAmbientTx(enabled bool) SQLCredo[T, I]
+
+	// WithTenant scopes all generated queries (reads, counts, pages, updates and
+	// deletes) to the tenant returned by fromContext, stored in the given column.
+	// Create and Update set the column of the entity to the tenant.
+	// Operations fail with api.ErrMissingTenant if the context has no tenant.
+	// Raw queries are not scoped.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithTenant(column string, fromContext api.TenantFunc) SQLCredo[T, I]
+
+	// WithSchema qualifies the table with the given database schema,
+	// e.g. "tenant_42" for "tenant_42"."users".
+	// Returns the modified SQLCredo instance for method chaining.
+	WithSchema(schema string) SQLCredo[T, I]
+
+	// WithSchemaResolver resolves the schema of the table for each query from
+	// its context, falling back to the schema set by WithSchema if it returns
+	// an empty string. Queries are then built per call instead of once.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithSchemaResolver(resolver api.SchemaFunc) SQLCredo[T, I]
+
+	// WithRelations registers relations to other repositories that can be
+	// preloaded with api.Preload and api.WithPreload.
+	// Returns the modified SQLCredo instance for method chaining.
+	WithRelations(relations ...Relation) SQLCredo[T, I]
+
+	// IDColumn returns the name of the ID column of the managed table.
+	IDColumn() string
+
+	// DriverName returns the database driver name the instance was created with.
+	DriverName() string
 }
 
 type sqlCredo[T any, I comparable] struct {
 	*sqlexec.SQLExecutor
 	*crud.CRUD[T, I]
 	*page.PageResolver[T]
+
+	tableInfo table.Info
+	relations map[string]Relation
 }
 
 var _ SQLCredo[any, string] = &sqlCredo[any, string]{}
@@ -65,6 +164,7 @@ func NewSQLCredo[T any, I comparable](db *sql.DB, driver string, tableName strin
 		SQLExecutor:  executor,
 		CRUD:         crud.NewCRUD[T, I](tableInfo, executor, driver),
 		PageResolver: page.NewPageResolver[T](tableInfo, executor, driver),
+		tableInfo:    tableInfo,
 	}
 }
 
@@ -72,13 +172,28 @@ func NewSQLCredo[T any, I comparable](db *sql.DB, driver string, tableName strin
 // This method is typically used during application startup to ensure
 // the required database structure exists.
 func (r *sqlCredo[T, I]) InitSchema(ctx context.Context, sql string) (sql.Result, error) {
-	res, err := r.Exec(ctx, sql)
+	res, err := r.Exec(api.WithQueryKind(ctx, api.QueryDDL), sql)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
 	return res, nil
 }
 
+// Patch sets the columns of the patch on the record identified by id.
+func (r *sqlCredo[T, I]) Patch(ctx context.Context, id I, patch Patch[T]) (sql.Result, error) {
+	return r.UpdateColumns(ctx, id, patch.Values())
+}
+
+// Verify compares T with the live table on the primary database.
+func (r *sqlCredo[T, I]) Verify(ctx context.Context) error {
+	ctx = api.WithPrimary(api.WithQueryKind(ctx, api.QueryDDL))
+	live, err := ddl.Inspect(ctx, r.SQLExecutor, r.DriverName(), r.tableInfo.SchemaName(ctx), r.tableInfo.Name)
+	if err != nil {
+		return err
+	}
+	return ddl.Verify(reflect.TypeOf((*T)(nil)).Elem(), live, r.tableInfo.Name, r.tableInfo.IDColumn)
+}
+
 // WithDebugFunc sets a new debug function for SQL query logging.
 // The debug function will be called before executing any SQL query,
 // allowing for query inspection and logging.
@@ -92,3 +207,90 @@ func (r *sqlCredo[T, I]) WithDebugFunc(newDebugFunc api.DebugFunc) SQLCredo[T, I
 func (r *sqlCredo[T, I]) GetDebugFunc() api.DebugFunc {
 	return r.DebugFunc
 }
+
+// WithStatementCache enables the LRU cache of prepared statements.
+// Argument-less Exec calls, typically DDL, are executed without preparing.
+func (r *sqlCredo[T, I]) WithStatementCache(size int) SQLCredo[T, I] {
+	r.EnableStatementCache(size)
+	return r
+}
+
+// WithReplicas sets the read replicas, opened with the same driver as the primary.
+func (r *sqlCredo[T, I]) WithReplicas(replicas ...*sql.DB) SQLCredo[T, I] {
+	dbs := make([]*sqlx.DB, 0, len(replicas))
+	for _, db := range replicas {
+		dbs = append(dbs, sqlx.NewDb(db, r.DriverName()))
+	}
+	r.SetReplicas(dbs...)
+	return r
+}
+
+// WithReplicaPicker sets the strategy choosing the replica for each read.
+func (r *sqlCredo[T, I]) WithReplicaPicker(picker api.ReplicaPicker) SQLCredo[T, I] {
+	r.SetReplicaPicker(picker)
+	return r
+}
+
+// Begin starts a transaction on the primary database.
+func (r *sqlCredo[T, I]) Begin(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := r.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	return NewTx(tx), nil
+}
+
+// WithRetryPolicy sets the policy retrying reads and transactions on transient errors.
+func (r *sqlCredo[T, I]) WithRetryPolicy(policy api.RetryPolicy) SQLCredo[T, I] {
+	r.SetRetryPolicy(policy)
+	return r
+}
+
+// WithTimeouts sets default timeouts per query kind.
+func (r *sqlCredo[T, I]) WithTimeouts(timeouts api.Timeouts) SQLCredo[T, I] {
+	r.SetTimeouts(timeouts)
+	return r
+}
+
+// WithMaxRows limits the number of records GetAll and GetAllInto may load.
+func (r *sqlCredo[T, I]) WithMaxRows(maxRows uint) SQLCredo[T, I] {
+	r.SetMaxRows(maxRows)
+	return r
+}
+
+// WithAmbientTx enables running queries in the transaction of the context.
+func (r *sqlCredo[T, I]) WithAmbientTx(enabled bool) SQLCredo[T, I] {
+	r.SetAmbientTx(enabled)
+	return r
+}
+
+// WithTenant scopes all generated queries to the tenant of the context.
+func (r *sqlCredo[T, I]) WithTenant(column string, fromContext api.TenantFunc) SQLCredo[T, I] {
+	r.tableInfo.Tenant = table.Tenant{Column: column, FromContext: fromContext}
+	r.setTable()
+	return r
+}
+
+// WithSchema qualifies the table with the given schema.
+func (r *sqlCredo[T, I]) WithSchema(schema string) SQLCredo[T, I] {
+	r.tableInfo.Schema = schema
+	r.setTable()
+	return r
+}
+
+// WithSchemaResolver resolves the schema of the table per context.
+func (r *sqlCredo[T, I]) WithSchemaResolver(resolver api.SchemaFunc) SQLCredo[T, I] {
+	r.tableInfo.SchemaFromContext = resolver
+	r.setTable()
+	return r
+}
+
+func (r *sqlCredo[T, I]) setTable() {
+	r.CRUD.SetTable(r.tableInfo)
+	r.PageResolver.SetTable(r.tableInfo)
+}
+
+// IDColumn returns the name of the ID column of the managed table.
+func (r *sqlCredo[T, I]) IDColumn() string {
+	return r.tableInfo.IDColumn
+}
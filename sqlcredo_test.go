@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -82,6 +83,29 @@ func TestSQLCredo_DebugFunc(t *testing.T) {
 	assert.True(t, debugCalled)
 }
 
+func TestSQLCredo_StatementCache(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.db.SetMaxOpenConns(1)
+
+	repo := c.UnderTest.WithStatementCache(8)
+	_, err := repo.InitSchema(ctx, `CREATE TABLE test_table (id TEXT PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	_, err = repo.Create(ctx, &TestEntity{ID: "1", Name: "John"})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		got, err := repo.GetByID(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "John", got.Name)
+	}
+
+	stats := repo.StatementCacheStats()
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, uint64(2), stats.Hits)
+}
+
 type testCaseData struct {
 	ctx       context.Context
 	ctxCancel func()
@@ -116,3 +140,25 @@ func (c *testCaseData) TearDown(t *testing.T) {
 	c.ctxCancel()
 	assert.NoError(t, c.db.Close())
 }
+
+func TestSQLCredo_MaxRows(t *testing.T) {
+	c, ctx := newTestCase(t)
+	c.db.SetMaxOpenConns(1)
+
+	repo := c.UnderTest.WithMaxRows(2)
+	_, err := repo.InitSchema(ctx, `CREATE TABLE test_table (id TEXT PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = repo.Create(ctx, &TestEntity{ID: fmt.Sprint(i), Name: "John"})
+		require.NoError(t, err)
+	}
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	_, err = repo.Create(ctx, &TestEntity{ID: "2", Name: "John"})
+	require.NoError(t, err)
+	_, err = repo.GetAll(ctx)
+	assert.ErrorIs(t, err, api.ErrTooManyRows)
+}
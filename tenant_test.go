@@ -0,0 +1,122 @@
+package sqlcredo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantEntity struct {
+	ID       string `db:"id"`
+	TenantID string `db:"tenant_id"`
+	Name     string `db:"name"`
+}
+
+type tenantKey struct{}
+
+func withTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func tenantFromContext(ctx context.Context) (any, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok
+}
+
+func TestSQLCredo_Tenant(t *testing.T) {
+	repo := newTenantRepo(t)
+	ctx := context.Background()
+	acme := withTenant(ctx, "acme")
+	globex := withTenant(ctx, "globex")
+
+	// the tenant of the context wins over the one of the entity
+	_, err := repo.Create(acme, &tenantEntity{ID: "1", TenantID: "globex", Name: "a1"})
+	require.NoError(t, err)
+	_, err = repo.Create(acme, &tenantEntity{ID: "2", Name: "a2"})
+	require.NoError(t, err)
+	_, err = repo.Create(globex, &tenantEntity{ID: "3", Name: "g1"})
+	require.NoError(t, err)
+
+	all, err := repo.GetAll(acme)
+	require.NoError(t, err)
+	assert.Equal(t, []tenantEntity{{"1", "acme", "a1"}, {"2", "acme", "a2"}}, all)
+
+	_, err = repo.GetByID(acme, "3")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	byIDs, err := repo.GetByIDs(globex, []string{"1", "2", "3"})
+	require.NoError(t, err)
+	assert.Equal(t, []tenantEntity{{"3", "globex", "g1"}}, byIDs)
+	exists, err := repo.ExistsByID(globex, "1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	count, err := repo.Count(acme)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+	page, err := repo.GetPage(globex)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), page.Total)
+
+	res, err := repo.Update(globex, "1", &tenantEntity{ID: "1", Name: "stolen"})
+	require.NoError(t, err)
+	assertRowsAffected(t, 0, res)
+	res, err = repo.Delete(globex, "2")
+	require.NoError(t, err)
+	assertRowsAffected(t, 0, res)
+
+	res, err = repo.DeleteAll(acme)
+	require.NoError(t, err)
+	assertRowsAffected(t, 2, res)
+	count, err = repo.Count(globex)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+}
+
+func TestSQLCredo_Tenant_Missing(t *testing.T) {
+	repo := newTenantRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.GetAll(ctx)
+	assert.ErrorIs(t, err, api.ErrMissingTenant)
+	_, err = repo.GetByID(ctx, "1")
+	assert.ErrorIs(t, err, api.ErrMissingTenant)
+	_, err = repo.Count(ctx)
+	assert.ErrorIs(t, err, api.ErrMissingTenant)
+	_, err = repo.GetPage(ctx)
+	assert.ErrorIs(t, err, api.ErrMissingTenant)
+	_, err = repo.Create(ctx, &tenantEntity{ID: "1"})
+	assert.ErrorIs(t, err, api.ErrMissingTenant)
+	_, err = repo.DeleteAll(ctx)
+	assert.ErrorIs(t, err, api.ErrMissingTenant)
+}
+
+func newTenantRepo(t *testing.T) sqlcredo.SQLCredo[tenantEntity, string] {
+	t.Helper()
+
+	db := openSQLite(t)
+
+	repo := sqlcredo.NewSQLCredo[tenantEntity, string](db, "sqlite3", "items", "id").
+		WithTenant("tenant_id", tenantFromContext)
+	_, err := repo.InitSchema(context.Background(), `CREATE TABLE items (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL,
+		name TEXT NOT NULL
+	)`)
+	require.NoError(t, err)
+
+	return repo
+}
+
+func assertRowsAffected(t *testing.T, expected int64, res sql.Result) {
+	t.Helper()
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, expected, n)
+}
@@ -0,0 +1,52 @@
+package sqlcredo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Klojer/sqlcredo/internal/sqlexec"
+	"github.com/Klojer/sqlcredo/pkg/api"
+)
+
+// Tx is a database transaction with savepoint support.
+// The embedded *sql.Tx is available for queries, Commit and Rollback.
+type Tx struct {
+	*sql.Tx
+}
+
+// NewTx wraps tx, e.g. the transaction passed to an InTx function.
+func NewTx(tx *sql.Tx) *Tx {
+	return &Tx{Tx: tx}
+}
+
+// Savepoint creates a savepoint with the given name, which must be a plain identifier.
+func (t *Tx) Savepoint(ctx context.Context, name string) error {
+	return sqlexec.Savepoint(ctx, t.Tx, name)
+}
+
+// RollbackTo rolls the transaction back to the savepoint with the given name.
+// The savepoint stays active and can be rolled back to again.
+func (t *Tx) RollbackTo(ctx context.Context, name string) error {
+	return sqlexec.RollbackTo(ctx, t.Tx, name)
+}
+
+// Release releases the savepoint with the given name, keeping its changes.
+func (t *Tx) Release(ctx context.Context, name string) error {
+	return sqlexec.Release(ctx, t.Tx, name)
+}
+
+// InTx runs fn within a savepoint of the transaction, released if fn succeeds
+// and rolled back otherwise, so fn can fail without aborting the transaction.
+// The context passed to fn carries the transaction for repositories with
+// ambient transactions enabled. Calls can be nested.
+func (t *Tx) InTx(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) error {
+	return sqlexec.InSavepoint(api.WithTxContext(ctx, t.Tx), t.Tx, func(ctx context.Context) error {
+		return fn(ctx, t)
+	})
+}
+
+// WithTxContext returns a context carrying tx as the ambient transaction,
+// joined by all repositories with WithAmbientTx enabled.
+func WithTxContext(ctx context.Context, tx *sql.Tx) context.Context {
+	return api.WithTxContext(ctx, tx)
+}
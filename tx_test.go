@@ -0,0 +1,99 @@
+package sqlcredo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Klojer/sqlcredo"
+	"github.com/Klojer/sqlcredo/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLCredo_AmbientTx(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteFile(t, "tx.db")
+	repo := sqlcredo.NewSQLCredo[TestEntity, string](db, "sqlite3", "test_table", "id").
+		WithAmbientTx(true)
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	txCtx := sqlcredo.WithTxContext(ctx, tx)
+
+	_, err = repo.Create(txCtx, &TestEntity{ID: "1", Name: "one"})
+	require.NoError(t, err)
+	count, err := repo.Count(txCtx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+	page, err := repo.GetPage(txCtx)
+	require.NoError(t, err)
+	assert.Len(t, page.Content, 1)
+
+	require.NoError(t, tx.Rollback())
+
+	count, err = repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestSQLCredo_AmbientTx_Disabled(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteFile(t, "tx.db")
+	repo := sqlcredo.NewSQLCredo[TestEntity, string](db, "sqlite3", "test_table", "id")
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	// the transaction of the context is ignored
+	_, err = repo.Create(sqlcredo.WithTxContext(ctx, tx), &TestEntity{ID: "1", Name: "one"})
+	require.NoError(t, err)
+}
+
+func TestSQLCredo_AmbientTx_Nested(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteFile(t, "tx.db")
+	repo := sqlcredo.NewSQLCredo[TestEntity, string](db, "sqlite3", "test_table", "id").
+		WithAmbientTx(true)
+	errInner := errors.New("inner failed")
+
+	err := repo.InTx(ctx, nil, func(ctx context.Context, _ *sql.Tx) error {
+		if _, err := repo.Create(ctx, &TestEntity{ID: "1", Name: "outer"}); err != nil {
+			return err
+		}
+
+		err := repo.InTx(ctx, nil, func(ctx context.Context, _ *sql.Tx) error {
+			if _, err := repo.Create(ctx, &TestEntity{ID: "2", Name: "inner"}); err != nil {
+				return err
+			}
+			return errInner
+		})
+		assert.ErrorIs(t, err, errInner)
+
+		return repo.InTx(ctx, nil, func(ctx context.Context, _ *sql.Tx) error {
+			_, err := repo.Create(ctx, &TestEntity{ID: "3", Name: "inner"})
+			return err
+		})
+	})
+	require.NoError(t, err)
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []TestEntity{{ID: "1", Name: "outer"}, {ID: "3", Name: "inner"}}, all)
+}
+
+func TestTx_Savepoint_InvalidName(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteFile(t, "tx.db")
+	repo := sqlcredo.NewSQLCredo[TestEntity, string](db, "sqlite3", "test_table", "id")
+
+	tx, err := repo.Begin(ctx, nil)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	err = tx.Savepoint(ctx, "sp; DROP TABLE test_table")
+	assert.ErrorIs(t, err, api.ErrInvalidSavepoint)
+}